@@ -9,6 +9,9 @@ import (
 
 var Config = loadConfig()
 
+// Version of the build, set with `-ldflags "-X github.com/maribowman/roastbeef-swag/app/config.Version=<tag>"`.
+var Version = "dev"
+
 type config struct {
 	Server   ServerConfig
 	Logging  LoggingConfig
@@ -40,6 +43,9 @@ func loadConfig() config {
 	if err != nil {
 		log.Fatal().Err(err).Msg("Unable to decode config into struct")
 	}
+	if err := config.Discord.applyDefaults(); err != nil {
+		log.Fatal().Err(err).Msg("Invalid discord channel config")
+	}
 
 	log.Info().Msgf("Starting service in %s mode", configFile)
 	return config
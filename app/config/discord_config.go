@@ -1,13 +1,116 @@
 package config
 
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+const (
+	ListChannelType     = "list"
+	MealPlanChannelType = "mealplan"
+)
+
+var tableNameRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
 type DiscordConfig struct {
-	Token    string
-	BotID    string
-	Channels []Channel
+	Token      string
+	BotID      string
+	Categories map[string][]string // keywords per category, e.g. `fish: [salmon, cod]`
+	Synonyms   map[string]string   // names which are merged into a canonical name, e.g. `aubergine: eggplant`
+	Recipes    map[string][]string // ingredients per recipe, e.g. `lasagne: [ 500g minced meat, 2 onions ]`
+	Channels   []Channel
 }
 
 type Channel struct {
-	Name      string
-	ID        string
-	LineBreak int `default:"100"`
+	Name       string
+	ID         string
+	Type       string
+	LineBreak  int `default:"100"`
+	Table      string
+	DateFormat string
+	Title      string
+	Columns    []string
+	Behaviours []string
+	Aisles     []string // categories in the order of the store, the list is sorted accordingly
+	Recurring  []string // items added again and again, e.g. `milk 2 every monday`
+	Stock      []string // lists checked for ingredients of a recipe before they are added, e.g. `tkGoods`
+	Groceries  string   // list the missing ingredients of a meal plan are added to
+	Expiry     Expiry
+	Restock    Restock
+}
+
+// Restock adds items to another list as soon as their stock falls below a minimum.
+type Restock struct {
+	List  string // name of the list items are added to
+	Rules []RestockRule
+}
+
+// RestockRule defines the minimum stock of an item or of all items of a category.
+type RestockRule struct {
+	Item     string
+	Category string
+	Minimum  float64 // restock when the stock falls below, defaults to 1
+	Amount   float64 // amount added to the list, defaults to 1
+}
+
+// Expiry configures the daily digest of items nearing their best-before date or stored longer than their shelf life.
+type Expiry struct {
+	Digest    string         // time of day like `08:00`, the digest is disabled if empty
+	WarnDays  int            // items expiring within these days are reported
+	ShelfLife map[string]int // days items of a category keep, `default` applies to all other items
+}
+
+// HasBehaviour reports whether a feature like the undo buttons is enabled for the channel.
+func (channel Channel) HasBehaviour(behaviour string) bool {
+	return contains(channel.Behaviours, behaviour)
+}
+
+func (discord *DiscordConfig) applyDefaults() error {
+	for index := range discord.Channels {
+		channel := &discord.Channels[index]
+		if channel.Type == "" {
+			channel.Type = ListChannelType
+		}
+		if channel.LineBreak <= 0 {
+			channel.LineBreak = 100
+		}
+		if channel.Table == "" {
+			channel.Table = channel.Name
+		}
+		if channel.DateFormat == "" {
+			channel.DateFormat = "02.01."
+		}
+		if channel.Title == "" {
+			channel.Title = fmt.Sprintf("Edit %s list", channel.Name)
+		}
+
+		if !tableNameRegex.MatchString(channel.Table) {
+			return fmt.Errorf("invalid table name `%s` for channel `%s`", channel.Table, channel.Name)
+		}
+		for ruleIndex := range channel.Restock.Rules {
+			rule := &channel.Restock.Rules[ruleIndex]
+			if (rule.Item == "") == (rule.Category == "") {
+				return fmt.Errorf("restock rule of channel `%s` needs either an item or a category", channel.Name)
+			}
+			if rule.Minimum <= 0 {
+				rule.Minimum = 1
+			}
+			if rule.Amount <= 0 {
+				rule.Amount = 1
+			}
+		}
+		if len(channel.Restock.Rules) != 0 && channel.Restock.List == "" {
+			return fmt.Errorf("restock rules of channel `%s` need a list", channel.Name)
+		}
+		if channel.Expiry.Digest != "" {
+			if _, err := time.Parse("15:04", channel.Expiry.Digest); err != nil {
+				return fmt.Errorf("invalid expiry digest time `%s` for channel `%s`", channel.Expiry.Digest, channel.Name)
+			}
+			if channel.Expiry.WarnDays <= 0 {
+				channel.Expiry.WarnDays = 7
+			}
+		}
+	}
+	return nil
 }
@@ -1,6 +1,7 @@
 package config
 
 type ServerConfig struct {
-	Port    int
-	Mode    string
+	Port     int
+	Mode     string
+	ApiToken string // bearer token for /api/v1, without it the API only answers requests from localhost
 }
@@ -1,28 +1,69 @@
 package controller
 
 import (
+	"crypto/subtle"
 	"github.com/gin-gonic/gin"
+	"github.com/maribowman/roastbeef-swag/app/model"
+	"net"
 	"net/http"
+	"strings"
 )
 
 type Controller struct {
 	router            *gin.Engine
 	prometheusHandler http.Handler
+	listHandlers      map[string]model.ListHandler
+	databaseClient    model.DatabaseClient
+	bot               model.DiscordBot
+	apiToken          string
 }
 
 type Wiring struct {
 	Router            *gin.Engine
 	PrometheusHandler http.Handler
+	ListHandlers      map[string]model.ListHandler
+	DatabaseClient    model.DatabaseClient
+	Bot               model.DiscordBot
+	ApiToken          string
 }
 
 func NewController(wiring *Wiring) {
 	controller := &Controller{
 		router:            wiring.Router,
 		prometheusHandler: wiring.PrometheusHandler,
+		listHandlers:      wiring.ListHandlers,
+		databaseClient:    wiring.DatabaseClient,
+		bot:               wiring.Bot,
+		apiToken:          wiring.ApiToken,
 	}
 	controller.router.Use(gin.Logger(), gin.Recovery())
 
 	controller.router.GET("/metrics", func(c *gin.Context) {
 		controller.prometheusHandler.ServeHTTP(c.Writer, c.Request)
 	})
+
+	controller.registerHealthRoutes()
+	controller.registerListRoutes()
+	controller.registerStatsRoutes()
+}
+
+// api groups the routes under /api/v1, which may change every list and therefore require authentication.
+func (controller *Controller) api() *gin.RouterGroup {
+	return controller.router.Group("/api/v1", controller.authenticate)
+}
+
+// authenticate accepts requests with the configured bearer token. Without a token only requests from localhost are
+// accepted, so the API is never open to everyone who can reach the port of the metrics.
+func (controller *Controller) authenticate(c *gin.Context) {
+	if controller.apiToken == "" {
+		if ip := net.ParseIP(c.RemoteIP()); ip == nil || !ip.IsLoopback() {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "the API only accepts requests from localhost unless `server.apiToken` is set"})
+		}
+		return
+	}
+	token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(controller.apiToken)) != 1 {
+		c.Header("WWW-Authenticate", "Bearer")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing or invalid bearer token"})
+	}
 }
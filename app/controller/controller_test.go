@@ -0,0 +1,90 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/maribowman/roastbeef-swag/app/model"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const testApiToken = "secret"
+
+// apiRequest creates a request to the API authenticated with testApiToken.
+func apiRequest(method, target string, body io.Reader) *http.Request {
+	request := httptest.NewRequest(method, target, body)
+	request.Header.Set("Authorization", "Bearer "+testApiToken)
+	return request
+}
+
+func TestAuthentication(t *testing.T) {
+	// given
+	gin.SetMode(gin.TestMode)
+	tests := map[string]struct {
+		apiToken       string
+		remoteAddr     string
+		authorization  string
+		expectedStatus int
+	}{
+		"valid token": {
+			apiToken:       testApiToken,
+			authorization:  "Bearer " + testApiToken,
+			expectedStatus: http.StatusOK,
+		},
+		"missing token": {
+			apiToken:       testApiToken,
+			remoteAddr:     "127.0.0.1:4711",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		"wrong token": {
+			apiToken:       testApiToken,
+			authorization:  "Bearer guess",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		"no token configured from localhost": {
+			remoteAddr:     "127.0.0.1:4711",
+			expectedStatus: http.StatusOK,
+		},
+		"no token configured from elsewhere": {
+			authorization:  "Bearer " + testApiToken,
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			handler := &fakeListHandler{items: []model.PantryItem{{Number: 1, Item: "eggs", Amount: 4}}}
+			router := gin.New()
+			NewController(&Wiring{
+				Router:            router,
+				PrometheusHandler: http.NotFoundHandler(),
+				ApiToken:          test.apiToken,
+				ListHandlers:      map[string]model.ListHandler{"groceries": handler},
+			})
+			request := httptest.NewRequest(http.MethodDelete, "/api/v1/lists/groceries/items?numbers=*", nil)
+			if test.remoteAddr != "" {
+				request.RemoteAddr = test.remoteAddr
+			}
+			if test.authorization != "" {
+				request.Header.Set("Authorization", test.authorization)
+			}
+
+			// when
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+
+			// then
+			assert.Equal(t, test.expectedStatus, recorder.Code, recorder.Body.String())
+			assert.Equal(t, test.expectedStatus != http.StatusOK, len(handler.items) == 1, "only authenticated requests change the list")
+		})
+	}
+
+	// and
+	router := gin.New()
+	NewController(&Wiring{Router: router, PrometheusHandler: http.NotFoundHandler(), ApiToken: testApiToken})
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code, "probes need no token")
+}
@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/maribowman/roastbeef-swag/app/config"
+	"github.com/rs/zerolog/log"
+	"net/http"
+	"time"
+)
+
+var startedAt = time.Now()
+
+type readinessResponse struct {
+	Ready  bool              `json:"ready"`
+	Checks map[string]string `json:"checks"`
+}
+
+type statusResponse struct {
+	Version  string                  `json:"version"`
+	Started  time.Time               `json:"started"`
+	Uptime   string                  `json:"uptime"`
+	Channels []channelStatusResponse `json:"channels"`
+}
+
+type channelStatusResponse struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Items *int   `json:"items,omitempty"` // only lists have items
+}
+
+func (controller *Controller) registerHealthRoutes() {
+	controller.router.GET("/healthz", controller.getHealth)
+	controller.router.GET("/readyz", controller.getReadiness)
+	controller.router.GET("/status", controller.getStatus)
+}
+
+// getHealth reports that the process is up.
+func (controller *Controller) getHealth(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// getReadiness reports whether the database can be reached and the bot is connected to Discord and ready.
+func (controller *Controller) getReadiness(c *gin.Context) {
+	response := readinessResponse{Ready: true, Checks: map[string]string{"sqlite": "ok", "discord": "ok"}}
+	if err := controller.databaseClient.Ping(); err != nil {
+		log.Warn().Err(err).Msg("Database is not reachable")
+		response.Ready = false
+		response.Checks["sqlite"] = err.Error()
+	}
+	if !controller.bot.IsReady() {
+		response.Ready = false
+		response.Checks["discord"] = "not connected or not ready"
+	}
+
+	status := http.StatusOK
+	if !response.Ready {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, response)
+}
+
+// getStatus lists version, uptime and the configured channels with the number of items of every list.
+func (controller *Controller) getStatus(c *gin.Context) {
+	response := statusResponse{
+		Version:  config.Version,
+		Started:  startedAt,
+		Uptime:   time.Since(startedAt).Truncate(time.Second).String(),
+		Channels: make([]channelStatusResponse, 0, len(config.Config.Discord.Channels)),
+	}
+	for _, channel := range config.Config.Discord.Channels {
+		channelStatus := channelStatusResponse{Name: channel.Name, Type: channel.Type}
+		if handler, ok := controller.listHandlers[channel.Name]; ok {
+			items, err := handler.GetItems()
+			if err != nil {
+				log.Error().Err(err).Msgf("Could not load list `%s`", channel.Name)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "could not process request"})
+				return
+			}
+			count := len(items)
+			channelStatus.Items = &count
+		}
+		response.Channels = append(response.Channels, channelStatus)
+	}
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,129 @@
+package controller
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/maribowman/roastbeef-swag/app/config"
+	"github.com/maribowman/roastbeef-swag/app/model"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type fakeDatabaseClient struct {
+	err error
+}
+
+func (client *fakeDatabaseClient) GetDatabaseConnection() *sql.DB {
+	return nil
+}
+
+func (client *fakeDatabaseClient) Ping() error {
+	return client.err
+}
+
+func (client *fakeDatabaseClient) CloseDatabaseConnection() {
+}
+
+type fakeDiscordBot struct {
+	model.DiscordBot
+	ready bool
+}
+
+func (bot *fakeDiscordBot) IsReady() bool {
+	return bot.ready
+}
+
+func TestReadiness(t *testing.T) {
+	// given
+	gin.SetMode(gin.TestMode)
+	tests := map[string]struct {
+		databaseErr    error
+		botReady       bool
+		expectedStatus int
+		expectedChecks map[string]string
+	}{
+		"ready": {
+			botReady:       true,
+			expectedStatus: http.StatusOK,
+			expectedChecks: map[string]string{"sqlite": "ok", "discord": "ok"},
+		},
+		"database unreachable": {
+			databaseErr:    errors.New("database is locked"),
+			botReady:       true,
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedChecks: map[string]string{"sqlite": "database is locked", "discord": "ok"},
+		},
+		"bot not ready": {
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedChecks: map[string]string{"sqlite": "ok", "discord": "not connected or not ready"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			router := gin.New()
+			NewController(&Wiring{
+				Router:            router,
+				PrometheusHandler: http.NotFoundHandler(),
+				DatabaseClient:    &fakeDatabaseClient{err: test.databaseErr},
+				Bot:               &fakeDiscordBot{ready: test.botReady},
+			})
+
+			// when
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			// then
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			var readiness readinessResponse
+			assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &readiness))
+			assert.Equal(t, test.expectedStatus == http.StatusOK, readiness.Ready)
+			assert.Equal(t, test.expectedChecks, readiness.Checks)
+
+			// and
+			recorder = httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+			assert.Equal(t, http.StatusOK, recorder.Code)
+		})
+	}
+}
+
+func TestStatus(t *testing.T) {
+	// given
+	gin.SetMode(gin.TestMode)
+	channels := config.Config.Discord.Channels
+	t.Cleanup(func() { config.Config.Discord.Channels = channels })
+	config.Config.Discord.Channels = []config.Channel{
+		{Name: "groceries", Type: config.ListChannelType},
+		{Name: "meals", Type: config.MealPlanChannelType},
+	}
+	router := gin.New()
+	NewController(&Wiring{
+		Router:            router,
+		PrometheusHandler: http.NotFoundHandler(),
+		ListHandlers: map[string]model.ListHandler{"groceries": &fakeListHandler{items: []model.PantryItem{
+			{Number: 1, Item: "eggs", Amount: 6},
+			{Number: 2, Item: "milk", Amount: 1},
+		}}},
+	})
+
+	// when
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/status", nil))
+
+	// then
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var status statusResponse
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &status))
+	assert.Equal(t, config.Version, status.Version)
+	assert.NotEmpty(t, status.Uptime)
+	items := 2
+	assert.Equal(t, []channelStatusResponse{
+		{Name: "groceries", Type: config.ListChannelType, Items: &items},
+		{Name: "meals", Type: config.MealPlanChannelType},
+	}, status.Channels)
+}
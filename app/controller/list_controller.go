@@ -0,0 +1,377 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/maribowman/roastbeef-swag/app/config"
+	"github.com/maribowman/roastbeef-swag/app/model"
+	"github.com/maribowman/roastbeef-swag/app/service"
+	"github.com/rs/zerolog/log"
+	"io"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const apiAuthor = "api"
+
+var errBadRequest = errors.New("bad request")
+
+type itemResponse struct {
+	Number   int        `json:"number"`
+	Item     string     `json:"item"`
+	Amount   float64    `json:"amount"`
+	Unit     string     `json:"unit,omitempty"`
+	Date     time.Time  `json:"date"`
+	Expires  *time.Time `json:"expires,omitempty"`
+	Note     string     `json:"note,omitempty"`
+	Category string     `json:"category,omitempty"`
+	Checked  bool       `json:"checked,omitempty"`
+	Author   string     `json:"author,omitempty"`
+}
+
+type itemRequest struct {
+	Item     string     `json:"item" binding:"required"`
+	Amount   float64    `json:"amount" binding:"omitempty,gt=0"`
+	Unit     string     `json:"unit"`
+	Expires  *time.Time `json:"expires"`
+	Category string     `json:"category"`
+}
+
+type itemPatchRequest struct {
+	Item     *string    `json:"item" binding:"omitempty,min=1"`
+	Amount   *float64   `json:"amount" binding:"omitempty,gt=0"`
+	Unit     *string    `json:"unit"`
+	Expires  *time.Time `json:"expires"`
+	Category *string    `json:"category"`
+	Checked  *bool      `json:"checked"`
+}
+
+// validate checks an item to add like the channel would and normalizes its unit. Binding does not check the items
+// of bulk requests, so all checks live here.
+func (request *itemRequest) validate() error {
+	if err := validateName(request.Item); err != nil {
+		return err
+	}
+	if request.Amount < 0 {
+		return errors.New("amount must be positive")
+	}
+	unit, err := validateUnit(request.Unit)
+	request.Unit = unit
+	return err
+}
+
+func validateName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return errors.New("item name must not be blank")
+	}
+	return nil
+}
+
+// validateUnit accepts the units and aliases of model.Units, so the API takes the same quantities as the channel.
+func validateUnit(unit string) (string, error) {
+	normalized := model.NormalizeUnit(unit)
+	if normalized != "" && !slices.Contains(model.Units, normalized) {
+		return "", fmt.Errorf("unknown unit `%s`, use one of %s", unit, strings.Join(model.Units, ", "))
+	}
+	return normalized, nil
+}
+
+func (controller *Controller) registerListRoutes() {
+	api := controller.api()
+	api.GET("/lists", controller.getLists)
+
+	items := api.Group("/lists/:list/items")
+	items.GET("", controller.getItems)
+	items.POST("", controller.addItem)
+	items.POST("/bulk", controller.addItems)
+	items.PATCH("/:number", controller.updateItem)
+	items.DELETE("/:number", controller.removeItem)
+	items.DELETE("", controller.removeItems)
+
+	export := api.Group("/lists/:list/export")
+	export.GET("", controller.exportItems)
+	export.PUT("", controller.importItems)
+}
+
+func (controller *Controller) getLists(c *gin.Context) {
+	lists := make([]string, 0, len(controller.listHandlers))
+	for list := range controller.listHandlers {
+		lists = append(lists, list)
+	}
+	sort.Strings(lists)
+	c.JSON(http.StatusOK, lists)
+}
+
+func (controller *Controller) getItems(c *gin.Context) {
+	handler, ok := controller.listHandler(c)
+	if !ok {
+		return
+	}
+	items, err := handler.GetItems()
+	if author := c.Query("author"); author != "" && err == nil {
+		items = service.ItemsBy(items, author)
+	}
+	controller.respondItems(c, http.StatusOK, items, err)
+}
+
+func (controller *Controller) addItem(c *gin.Context) {
+	var request itemRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := request.validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	controller.add(c, []itemRequest{request})
+}
+
+// addItems adds all items of a JSON array at once, resulting in a single list revision.
+func (controller *Controller) addItems(c *gin.Context) {
+	var requests []itemRequest
+	if err := c.ShouldBindJSON(&requests); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	for index := range requests {
+		if err := requests[index].validate(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("item %d: %s", index+1, err)})
+			return
+		}
+	}
+	controller.add(c, requests)
+}
+
+func (controller *Controller) add(c *gin.Context, requests []itemRequest) {
+	handler, ok := controller.listHandler(c)
+	if !ok {
+		return
+	}
+
+	items, err := handler.ChangeItems(apiAuthor, func(items []model.PantryItem) ([]model.PantryItem, error) {
+		for _, request := range requests {
+			amount := request.Amount
+			if amount == 0 {
+				amount = 1
+			}
+			item := model.PantryItem{
+				Item:     strings.TrimSpace(request.Item),
+				Amount:   amount,
+				Unit:     request.Unit,
+				Date:     time.Now().Truncate(time.Minute),
+				Category: strings.ToLower(strings.TrimSpace(request.Category)),
+			}
+			if request.Expires != nil {
+				item.Expires = *request.Expires
+			}
+			items = service.MergeItem(items, item)
+		}
+		return items, nil
+	})
+	controller.respondItems(c, http.StatusCreated, items, err)
+}
+
+func (controller *Controller) updateItem(c *gin.Context) {
+	handler, ok := controller.listHandler(c)
+	if !ok {
+		return
+	}
+	number, err := strconv.Atoi(c.Param("number"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "item number must be numeric"})
+		return
+	}
+	var request itemPatchRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if request.Item != nil {
+		if err := validateName(*request.Item); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if request.Unit != nil {
+		unit, err := validateUnit(*request.Unit)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		request.Unit = &unit
+	}
+
+	items, err := handler.ChangeItems(apiAuthor, func(items []model.PantryItem) ([]model.PantryItem, error) {
+		if number < 1 || number > len(items) {
+			return nil, fmt.Errorf("%w: there is no item #%d", errBadRequest, number)
+		}
+		updatedItems := append([]model.PantryItem{}, items...)
+		if request.Item != nil {
+			updatedItems[number-1].Item = strings.TrimSpace(*request.Item)
+		}
+		if request.Amount != nil {
+			updatedItems[number-1].Amount = *request.Amount
+		}
+		if request.Unit != nil {
+			updatedItems[number-1].Unit = *request.Unit
+		}
+		if request.Expires != nil {
+			updatedItems[number-1].Expires = *request.Expires
+		}
+		if request.Category != nil {
+			updatedItems[number-1].Category = strings.ToLower(strings.TrimSpace(*request.Category))
+		}
+		if request.Checked != nil {
+			updatedItems[number-1].Checked = *request.Checked
+		}
+		return updatedItems, nil
+	})
+	controller.respondItems(c, http.StatusOK, items, err)
+}
+
+func (controller *Controller) removeItem(c *gin.Context) {
+	if _, err := strconv.Atoi(c.Param("number")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "item number must be numeric"})
+		return
+	}
+	controller.remove(c, c.Param("number"))
+}
+
+// removeItems removes items by the same syntax as in the channel, e.g. `?numbers=1 4-7` or `?numbers=* 2`.
+func (controller *Controller) removeItems(c *gin.Context) {
+	numbers, ok := c.GetQuery("numbers")
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "query parameter `numbers` is required"})
+		return
+	}
+	controller.remove(c, numbers)
+}
+
+func (controller *Controller) remove(c *gin.Context, expression string) {
+	handler, ok := controller.listHandler(c)
+	if !ok {
+		return
+	}
+
+	items, err := handler.ChangeItems(apiAuthor, func(items []model.PantryItem) ([]model.PantryItem, error) {
+		updatedItems, err := service.RemoveItems(items, expression)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errBadRequest, err)
+		}
+		return updatedItems, nil
+	})
+	controller.respondItems(c, http.StatusOK, items, err)
+}
+
+// exportItems downloads a list as CSV, JSON or plain text, e.g. `?format=json`. CSV is the default.
+func (controller *Controller) exportItems(c *gin.Context) {
+	handler, ok := controller.listHandler(c)
+	if !ok {
+		return
+	}
+	format := c.DefaultQuery("format", service.CSVFormat)
+	if !slices.Contains(service.ExportFormats, format) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("format must be one of %s", strings.Join(service.ExportFormats, ", "))})
+		return
+	}
+
+	items, err := handler.GetItems()
+	if err != nil {
+		log.Error().Err(err).Msgf("Could not load list `%s`", c.Param("list"))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not process request"})
+		return
+	}
+	export, err := service.ExportItems(items, format)
+	if err != nil {
+		log.Error().Err(err).Msgf("Could not export list `%s`", c.Param("list"))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not process request"})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", service.ExportFileName(c.Param("list"), format)))
+	c.Data(http.StatusOK, service.ExportContentType(format), export)
+}
+
+// importItems loads an export into a list. The format is taken from `?format=` or the content type, `?mode=replace`
+// replaces the list instead of merging the items into it.
+func (controller *Controller) importItems(c *gin.Context) {
+	handler, ok := controller.listHandler(c)
+	if !ok {
+		return
+	}
+	format := c.Query("format")
+	if format == "" {
+		format = service.FormatOf("", c.ContentType())
+	}
+	if !slices.Contains(service.ExportFormats, format) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("format must be one of %s", strings.Join(service.ExportFormats, ", "))})
+		return
+	}
+	mode := c.DefaultQuery("mode", service.MergeMode)
+	if !slices.Contains(service.ImportModes, mode) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("mode must be one of %s", strings.Join(service.ImportModes, ", "))})
+		return
+	}
+	content, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, service.MaxImportSize))
+	if maxBytesErr := (*http.MaxBytesError)(nil); errors.As(err, &maxBytesErr) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("body must not exceed %d KiB", service.MaxImportSize>>10)})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	imported, err := service.ImportItems(content, format)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	items, err := handler.ChangeItems(apiAuthor, func(items []model.PantryItem) ([]model.PantryItem, error) {
+		return service.ApplyImport(items, imported, mode)
+	})
+	controller.respondItems(c, http.StatusOK, items, err)
+}
+
+func (controller *Controller) listHandler(c *gin.Context) (model.ListHandler, bool) {
+	handler, ok := controller.listHandlers[c.Param("list")]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("unknown list `%s`", c.Param("list"))})
+	}
+	return handler, ok
+}
+
+func (controller *Controller) respondItems(c *gin.Context, status int, items []model.PantryItem, err error) {
+	if errors.Is(err, errBadRequest) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	} else if err != nil {
+		log.Error().Err(err).Msgf("Could not process request on list `%s`", c.Param("list"))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not process request"})
+		return
+	}
+
+	response := make([]itemResponse, 0, len(items))
+	for _, item := range items {
+		itemResponse := itemResponse{
+			Number:   item.Number,
+			Item:     item.Item,
+			Amount:   item.Amount,
+			Unit:     item.Unit,
+			Date:     item.Date,
+			Note:     item.Note,
+			Category: model.CategoryOf(item, config.Config.Discord.Categories),
+			Checked:  item.Checked,
+			Author:   item.Author,
+		}
+		if !item.Expires.IsZero() {
+			itemResponse.Expires = &item.Expires
+		}
+		response = append(response, itemResponse)
+	}
+	c.JSON(status, response)
+}
@@ -0,0 +1,324 @@
+package controller
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/maribowman/roastbeef-swag/app/model"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeListHandler struct {
+	items    []model.PantryItem
+	authors  []string
+	removals []model.Removal
+}
+
+func (handler *fakeListHandler) GetItems() ([]model.PantryItem, error) {
+	return handler.items, nil
+}
+
+func (handler *fakeListHandler) ChangeItems(author string, change func([]model.PantryItem) ([]model.PantryItem, error)) ([]model.PantryItem, error) {
+	items, err := change(handler.items)
+	if err != nil {
+		return nil, err
+	}
+	for index := range items {
+		items[index].Number = index + 1
+	}
+	handler.items = items
+	handler.authors = append(handler.authors, author)
+	return items, nil
+}
+
+func (handler *fakeListHandler) MoveItems(string, []int, model.ListHandler) ([]model.PantryItem, error) {
+	return nil, nil
+}
+
+func (handler *fakeListHandler) GetRemovals(time.Time) ([]model.Removal, error) {
+	return handler.removals, nil
+}
+
+func TestListRoutes(t *testing.T) {
+	// given
+	tests := map[string]struct {
+		method         string
+		path           string
+		body           string
+		expectedStatus int
+		expectedItems  []string
+	}{
+		"get items": {
+			method:         http.MethodGet,
+			path:           "/api/v1/lists/groceries/items",
+			expectedStatus: http.StatusOK,
+			expectedItems:  []string{"eggs", "milk", "coffee"},
+		},
+		"unknown list": {
+			method:         http.MethodGet,
+			path:           "/api/v1/lists/wine/items",
+			expectedStatus: http.StatusNotFound,
+			expectedItems:  []string{"eggs", "milk", "coffee"},
+		},
+		"add item": {
+			method:         http.MethodPost,
+			path:           "/api/v1/lists/groceries/items",
+			body:           `{"item": "bacon", "amount": 2}`,
+			expectedStatus: http.StatusCreated,
+			expectedItems:  []string{"eggs", "milk", "coffee", "bacon"},
+		},
+		"add item without name": {
+			method:         http.MethodPost,
+			path:           "/api/v1/lists/groceries/items",
+			body:           `{"amount": 2}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedItems:  []string{"eggs", "milk", "coffee"},
+		},
+		"add item with blank name": {
+			method:         http.MethodPost,
+			path:           "/api/v1/lists/groceries/items",
+			body:           `{"item": "   "}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedItems:  []string{"eggs", "milk", "coffee"},
+		},
+		"add item with unknown unit": {
+			method:         http.MethodPost,
+			path:           "/api/v1/lists/groceries/items",
+			body:           `{"item": "bacon", "unit": "slices"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedItems:  []string{"eggs", "milk", "coffee"},
+		},
+		"bulk add with unknown unit": {
+			method:         http.MethodPost,
+			path:           "/api/v1/lists/groceries/items/bulk",
+			body:           `[{"item": "bacon"}, {"item": "beer", "unit": "crates"}]`,
+			expectedStatus: http.StatusBadRequest,
+			expectedItems:  []string{"eggs", "milk", "coffee"},
+		},
+		"bulk add": {
+			method:         http.MethodPost,
+			path:           "/api/v1/lists/groceries/items/bulk",
+			body:           `[{"item": "bacon"}, {"item": "beer", "amount": 6}]`,
+			expectedStatus: http.StatusCreated,
+			expectedItems:  []string{"eggs", "milk", "coffee", "bacon", "beer"},
+		},
+		"update item": {
+			method:         http.MethodPatch,
+			path:           "/api/v1/lists/groceries/items/2",
+			body:           `{"item": "oat milk"}`,
+			expectedStatus: http.StatusOK,
+			expectedItems:  []string{"eggs", "oat milk", "coffee"},
+		},
+		"update item with blank name": {
+			method:         http.MethodPatch,
+			path:           "/api/v1/lists/groceries/items/2",
+			body:           `{"item": "   "}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedItems:  []string{"eggs", "milk", "coffee"},
+		},
+		"update unknown item": {
+			method:         http.MethodPatch,
+			path:           "/api/v1/lists/groceries/items/7",
+			body:           `{"amount": 3}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedItems:  []string{"eggs", "milk", "coffee"},
+		},
+		"remove item": {
+			method:         http.MethodDelete,
+			path:           "/api/v1/lists/groceries/items/1",
+			expectedStatus: http.StatusOK,
+			expectedItems:  []string{"milk", "coffee"},
+		},
+		"remove range": {
+			method:         http.MethodDelete,
+			path:           "/api/v1/lists/groceries/items?numbers=*%202",
+			expectedStatus: http.StatusOK,
+			expectedItems:  []string{"milk"},
+		},
+		"remove without numbers": {
+			method:         http.MethodDelete,
+			path:           "/api/v1/lists/groceries/items",
+			expectedStatus: http.StatusBadRequest,
+			expectedItems:  []string{"eggs", "milk", "coffee"},
+		},
+		"remove with invalid expression": {
+			method:         http.MethodDelete,
+			path:           "/api/v1/lists/groceries/items?numbers=eggs",
+			expectedStatus: http.StatusBadRequest,
+			expectedItems:  []string{"eggs", "milk", "coffee"},
+		},
+		"import csv": {
+			method:         http.MethodPut,
+			path:           "/api/v1/lists/groceries/export?format=csv",
+			body:           "item,amount\nbacon,2\nmilk,1\n",
+			expectedStatus: http.StatusOK,
+			expectedItems:  []string{"eggs", "milk", "coffee", "bacon"},
+		},
+		"replace with text": {
+			method:         http.MethodPut,
+			path:           "/api/v1/lists/groceries/export?format=text&mode=replace",
+			body:           "bacon\n6 beer\n",
+			expectedStatus: http.StatusOK,
+			expectedItems:  []string{"bacon", "beer"},
+		},
+		"import without format": {
+			method:         http.MethodPut,
+			path:           "/api/v1/lists/groceries/export",
+			body:           "bacon",
+			expectedStatus: http.StatusBadRequest,
+			expectedItems:  []string{"eggs", "milk", "coffee"},
+		},
+		"import with unknown mode": {
+			method:         http.MethodPut,
+			path:           "/api/v1/lists/groceries/export?format=text&mode=append",
+			body:           "bacon",
+			expectedStatus: http.StatusBadRequest,
+			expectedItems:  []string{"eggs", "milk", "coffee"},
+		},
+		"import invalid csv": {
+			method:         http.MethodPut,
+			path:           "/api/v1/lists/groceries/export?format=csv",
+			body:           "name\nbacon\n",
+			expectedStatus: http.StatusBadRequest,
+			expectedItems:  []string{"eggs", "milk", "coffee"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// and
+			gin.SetMode(gin.TestMode)
+			handler := &fakeListHandler{items: []model.PantryItem{
+				{Number: 1, Item: "eggs", Amount: 4},
+				{Number: 2, Item: "milk", Amount: 1},
+				{Number: 3, Item: "coffee", Amount: 1},
+			}}
+			router := gin.New()
+			NewController(&Wiring{
+				Router:            router,
+				PrometheusHandler: http.NotFoundHandler(),
+				ApiToken:          testApiToken,
+				ListHandlers:      map[string]model.ListHandler{"groceries": handler},
+			})
+
+			// when
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, apiRequest(test.method, test.path, strings.NewReader(test.body)))
+
+			// then
+			assert.Equal(t, test.expectedStatus, recorder.Code, recorder.Body.String())
+			var actualItems []string
+			for _, item := range handler.items {
+				actualItems = append(actualItems, item.Item)
+			}
+			assert.Equal(t, test.expectedItems, actualItems)
+		})
+	}
+}
+
+func TestGetItemsByAuthor(t *testing.T) {
+	// given
+	gin.SetMode(gin.TestMode)
+	handler := &fakeListHandler{items: []model.PantryItem{
+		{Number: 1, Item: "eggs", Amount: 4, Author: "mari"},
+		{Number: 2, Item: "the blue sauce", Amount: 1, Author: "tom, mari"},
+		{Number: 3, Item: "coffee", Amount: 1, Author: "tom"},
+	}}
+	router := gin.New()
+	NewController(&Wiring{
+		Router:            router,
+		PrometheusHandler: http.NotFoundHandler(),
+		ApiToken:          testApiToken,
+		ListHandlers:      map[string]model.ListHandler{"groceries": handler},
+	})
+
+	// when
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, apiRequest(http.MethodGet, "/api/v1/lists/groceries/items?author=tom", nil))
+
+	// then
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var actual []itemResponse
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &actual))
+	assert.Len(t, actual, 2)
+	assert.Equal(t, 2, actual[0].Number)
+	assert.Equal(t, "tom, mari", actual[0].Author)
+	assert.Equal(t, "coffee", actual[1].Item)
+}
+
+func TestExportItems(t *testing.T) {
+	// given
+	gin.SetMode(gin.TestMode)
+	handler := &fakeListHandler{items: []model.PantryItem{
+		{Number: 1, Item: "fish sticks", Amount: 2, Unit: "packs", Expires: time.Date(2027, 1, 31, 0, 0, 0, 0, time.Local)},
+		{Number: 2, Item: "peas", Amount: 1, Category: "vegetables"},
+	}}
+	router := gin.New()
+	NewController(&Wiring{
+		Router:            router,
+		PrometheusHandler: http.NotFoundHandler(),
+		ApiToken:          testApiToken,
+		ListHandlers:      map[string]model.ListHandler{"tkGoods": handler},
+	})
+	tests := map[string]struct {
+		path                string
+		expectedStatus      int
+		expectedContentType string
+		expectedFileName    string
+		expectedBody        string
+	}{
+		"csv by default": {
+			path:                "/api/v1/lists/tkGoods/export",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/csv; charset=utf-8",
+			expectedFileName:    "tkGoods.csv",
+			expectedBody:        "item,amount,unit,date,expires,note,category,checked,author\n",
+		},
+		"text": {
+			path:                "/api/v1/lists/tkGoods/export?format=text",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/plain; charset=utf-8",
+			expectedFileName:    "tkGoods.txt",
+			expectedBody:        "[1] 2 packs fish sticks exp 31.01.27\n[2] 1 peas #vegetables\n",
+		},
+		"unknown format": {
+			path:           "/api/v1/lists/tkGoods/export?format=xml",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// when
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, apiRequest(http.MethodGet, test.path, nil))
+
+			// then
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			if test.expectedStatus != http.StatusOK {
+				return
+			}
+			assert.Equal(t, test.expectedContentType, recorder.Header().Get("Content-Type"))
+			assert.Equal(t, `attachment; filename="`+test.expectedFileName+`"`, recorder.Header().Get("Content-Disposition"))
+			assert.True(t, strings.HasPrefix(recorder.Body.String(), test.expectedBody), recorder.Body.String())
+		})
+	}
+
+	// and when
+	recorder := httptest.NewRecorder()
+	request := apiRequest(http.MethodPut, "/api/v1/lists/tkGoods/export?mode=replace", strings.NewReader(`[{"item": "ice cream"}]`))
+	request.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(recorder, request)
+
+	// then
+	assert.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+	var actual []itemResponse
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &actual))
+	assert.Len(t, actual, 1)
+	assert.Equal(t, "ice cream", actual[0].Item)
+	assert.Equal(t, []string{apiAuthor}, handler.authors)
+}
@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/maribowman/roastbeef-swag/app/model"
+	"github.com/maribowman/roastbeef-swag/app/service"
+	"github.com/rs/zerolog/log"
+	"net/http"
+	"sort"
+)
+
+type statsResponse struct {
+	List        string              `json:"list"`
+	Consumed    int                 `json:"consumed"`
+	Reasons     map[string]int      `json:"reasons"`
+	AverageDays float64             `json:"averageDays"`
+	TopItems    []itemCountResponse `json:"topItems"`
+	Weeks       []weekCountResponse `json:"weeks"`
+}
+
+type itemCountResponse struct {
+	Item  string `json:"item"`
+	Count int    `json:"count"`
+}
+
+type weekCountResponse struct {
+	Week  string `json:"week"`
+	Count int    `json:"count"`
+}
+
+func (controller *Controller) registerStatsRoutes() {
+	controller.api().GET("/stats", controller.getStats)
+}
+
+// getStats returns the stats of all lists, or only of the list given by `?list=groceries`.
+func (controller *Controller) getStats(c *gin.Context) {
+	var lists []string
+	if list := c.Query("list"); list != "" {
+		if _, ok := controller.listHandlers[list]; !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("there is no list `%s`", list)})
+			return
+		}
+		lists = append(lists, list)
+	} else {
+		for list := range controller.listHandlers {
+			lists = append(lists, list)
+		}
+		sort.Strings(lists)
+	}
+
+	response := make([]statsResponse, 0, len(lists))
+	for _, list := range lists {
+		stats, err := service.CalculateStats(controller.listHandlers[list], list)
+		if err != nil {
+			log.Error().Err(err).Msgf("Could not calculate stats of list `%s`", list)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not process request"})
+			return
+		}
+		response = append(response, toStatsResponse(stats))
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func toStatsResponse(stats model.Stats) statsResponse {
+	response := statsResponse{
+		List:        stats.List,
+		Consumed:    stats.Consumed,
+		Reasons:     stats.Reasons,
+		AverageDays: stats.AverageDays,
+		TopItems:    make([]itemCountResponse, 0, len(stats.TopItems)),
+		Weeks:       make([]weekCountResponse, 0, len(stats.Weeks)),
+	}
+	for _, item := range stats.TopItems {
+		response.TopItems = append(response.TopItems, itemCountResponse{Item: item.Item, Count: item.Count})
+	}
+	for _, week := range stats.Weeks {
+		response.Weeks = append(response.Weeks, weekCountResponse{Week: week.Week, Count: week.Count})
+	}
+	return response
+}
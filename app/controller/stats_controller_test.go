@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/maribowman/roastbeef-swag/app/model"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGetStats(t *testing.T) {
+	// given
+	gin.SetMode(gin.TestMode)
+	now := time.Now()
+	groceries := &fakeListHandler{removals: []model.Removal{
+		{Item: "coffee", Amount: 1, Reason: model.PurchasedReason, Added: now.AddDate(0, 0, -3), Removed: now.AddDate(0, 0, -1)},
+		{Item: "eggs", Amount: 6, Reason: model.ClearedReason, Added: now.AddDate(0, 0, -5), Removed: now.AddDate(0, 0, -1)},
+		{Item: "mlik", Amount: 1, Reason: model.RemovedReason, Added: now, Removed: now},
+		{Item: "coffee", Amount: 1, Reason: model.MovedReason, Added: now, Removed: now},
+	}}
+	router := gin.New()
+	NewController(&Wiring{
+		Router:            router,
+		PrometheusHandler: http.NotFoundHandler(),
+		ApiToken:          testApiToken,
+		ListHandlers:      map[string]model.ListHandler{"groceries": groceries, "tk": &fakeListHandler{}},
+	})
+
+	// when
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, apiRequest(http.MethodGet, "/api/v1/stats", nil))
+
+	// then
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var stats []statsResponse
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &stats))
+	assert.Len(t, stats, 2)
+	assert.Equal(t, "groceries", stats[0].List)
+	assert.Equal(t, 2, stats[0].Consumed)
+	assert.Equal(t, map[string]int{"purchased": 1, "cleared": 1, "removed": 1, "moved": 1}, stats[0].Reasons)
+	assert.Equal(t, 3.0, stats[0].AverageDays)
+	assert.Equal(t, []itemCountResponse{{Item: "coffee", Count: 1}, {Item: "egg", Count: 1}}, stats[0].TopItems)
+	assert.Len(t, stats[0].Weeks, 8)
+	assert.Equal(t, "tk", stats[1].List)
+	assert.Empty(t, stats[1].TopItems)
+
+	// and
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, apiRequest(http.MethodGet, "/api/v1/stats?list=wine", nil))
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
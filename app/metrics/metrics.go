@@ -0,0 +1,75 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"time"
+)
+
+const namespace = "roastbeef"
+
+var (
+	ItemsAdded = register(prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "items_added_total",
+		Help:      "Items added to a list, including items moved in from another list.",
+	}, []string{"channel"}))
+	ItemsRemoved = register(prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "items_removed_total",
+		Help:      "Items removed from a list, including items moved to another list.",
+	}, []string{"channel"}))
+	ListItems = register(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "list_items",
+		Help:      "Items currently on a list, as last published.",
+	}, []string{"channel"}))
+	Undos = register(prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "undo_total",
+		Help:      "Undone and redone changes by direction.",
+	}, []string{"channel", "direction"}))
+	ParseFailures = register(prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "parse_failures_total",
+		Help:      "Lines of messages or commands which could not be understood.",
+	}, []string{"channel"}))
+	DiscordRequestDuration = register(prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "discord_request_duration_seconds",
+		Help:      "Latency of calls to the Discord API.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"call"}))
+	DiscordRequestErrors = register(prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "discord_request_errors_total",
+		Help:      "Failed calls to the Discord API.",
+	}, []string{"call"}))
+	SqliteQueryDuration = register(prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "sqlite_query_duration_seconds",
+		Help:      "Latency of database operations, which may run several queries in one transaction.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+	}, []string{"store", "operation"}))
+)
+
+func register[T prometheus.Collector](collector T) T {
+	prometheus.MustRegister(collector)
+	return collector
+}
+
+// ObserveDiscordCall records the latency of a Discord API call and whether it failed.
+func ObserveDiscordCall(call string, start time.Time, err error) {
+	DiscordRequestDuration.WithLabelValues(call).Observe(time.Since(start).Seconds())
+	if err != nil {
+		DiscordRequestErrors.WithLabelValues(call).Inc()
+	}
+}
+
+// ObserveQuery starts timing a database operation. The returned func records the latency, usually deferred like
+// `defer metrics.ObserveQuery("pantry", "get_items")()`.
+func ObserveQuery(store, operation string) func() {
+	start := time.Now()
+	return func() {
+		SqliteQueryDuration.WithLabelValues(store, operation).Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"slices"
+	"strings"
+)
+
+// Categorize returns the first category, in alphabetical order, with a keyword contained in the words of the item.
+// Items without a matching keyword have no category.
+func Categorize(item string, categories map[string][]string) string {
+	words := strings.Fields(strings.ToLower(item))
+	names := make([]string, 0, len(categories))
+	for name := range categories {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		for _, keyword := range categories[name] {
+			if slices.Contains(words, strings.ToLower(keyword)) {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// CategoryOf returns the explicitly tagged category of an item or else the category matched by its keywords.
+func CategoryOf(item PantryItem, categories map[string][]string) string {
+	if item.Category != "" {
+		return item.Category
+	}
+	return Categorize(item.Item, categories)
+}
+
+// SortByAisle orders items by the position of their category in the aisle order. Items of unknown categories follow
+// at the end, the order of items within an aisle is kept.
+func SortByAisle(items []PantryItem, aisles []string, categories map[string][]string) []PantryItem {
+	if len(aisles) == 0 {
+		return items
+	}
+	aisle := func(item PantryItem) int {
+		if index := slices.Index(aisles, CategoryOf(item, categories)); index != -1 {
+			return index
+		}
+		return len(aisles)
+	}
+
+	sorted := slices.Clone(items)
+	slices.SortStableFunc(sorted, func(a, b PantryItem) int {
+		return aisle(a) - aisle(b)
+	})
+	return sorted
+}
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCategorize(t *testing.T) {
+	// given
+	categories := map[string][]string{
+		"fish":       {"salmon", "cod"},
+		"vegetables": {"peas", "spinach"},
+		"seafood":    {"salmon", "shrimps"},
+	}
+
+	// then
+	assert.Equal(t, "fish", Categorize("Smoked Salmon", categories))
+	assert.Equal(t, "vegetables", Categorize("peas", categories))
+	assert.Equal(t, "", Categorize("peasant bread", categories))
+	assert.Equal(t, "", Categorize("pizza", nil))
+}
+
+func TestSortByAisle(t *testing.T) {
+	// given
+	categories := map[string][]string{"dairy": {"milk", "butter"}, "produce": {"apples", "salad"}}
+	items := []PantryItem{
+		{ID: 1, Item: "milk"},
+		{ID: 2, Item: "batteries"},
+		{ID: 3, Item: "apples"},
+		{ID: 4, Item: "cream", Category: "dairy"},
+		{ID: 5, Item: "salad"},
+	}
+
+	// when
+	sorted := SortByAisle(items, []string{"produce", "bakery", "dairy"}, categories)
+
+	// then
+	var ids []int
+	for _, item := range sorted {
+		ids = append(ids, item.ID)
+	}
+	assert.Equal(t, []int{3, 5, 1, 4, 2}, ids)
+	assert.Equal(t, 1, items[0].ID) // original list stays untouched
+	assert.Equal(t, items, SortByAisle(items, nil, categories))
+}
@@ -0,0 +1,114 @@
+package model
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/olekukonko/tablewriter"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const maxDescribedItems = 5
+
+type Revision struct {
+	ID      int
+	Author  string
+	Action  string
+	Items   []PantryItem
+	Date    time.Time
+	Current bool // set if the list is currently at this revision
+}
+
+// DescribeChanges summarizes the difference between two stored lists, e.g. `added 3 eggs; removed milk`.
+// Items are matched by their database ID, so renumbering alone is not considered a change.
+func DescribeChanges(oldItems, newItems []PantryItem) string {
+	oldItemsByID := map[int]PantryItem{}
+	for _, item := range oldItems {
+		oldItemsByID[item.ID] = item
+	}
+
+	var added, changed, ticked, unticked, removed []string
+	for _, item := range newItems {
+		oldItem, ok := oldItemsByID[item.ID]
+		if !ok {
+			added = append(added, DescribeItem(item))
+			continue
+		}
+		if oldItem.Item != item.Item || oldItem.Amount != item.Amount || oldItem.Unit != item.Unit || !oldItem.Expires.Equal(item.Expires) {
+			changed = append(changed, DescribeItem(item))
+		} else if !oldItem.Checked && item.Checked {
+			ticked = append(ticked, DescribeItem(item))
+		} else if oldItem.Checked && !item.Checked {
+			unticked = append(unticked, DescribeItem(item))
+		}
+		delete(oldItemsByID, item.ID)
+	}
+	for _, item := range oldItems {
+		if _, ok := oldItemsByID[item.ID]; ok {
+			removed = append(removed, DescribeItem(item))
+		}
+	}
+
+	var changes []string
+	for _, change := range []struct {
+		verb  string
+		items []string
+	}{{"added", added}, {"changed", changed}, {"ticked", ticked}, {"unticked", unticked}, {"removed", removed}} {
+		if len(change.items) == 0 {
+			continue
+		}
+		if len(change.items) > maxDescribedItems {
+			change.items = append(change.items[:maxDescribedItems], fmt.Sprintf("+%d more", len(change.items)-maxDescribedItems))
+		}
+		changes = append(changes, change.verb+" "+strings.Join(change.items, ", "))
+	}
+	return strings.Join(changes, "; ")
+}
+
+// DescribeItem names an item with its quantity, unless it is a single piece.
+func DescribeItem(item PantryItem) string {
+	if item.Amount == 1 && item.Unit == "" {
+		return item.Item
+	}
+	return fmt.Sprintf("%s %s", FormatQuantity(item.Amount, item.Unit), item.Item)
+}
+
+// ToHistoryTable renders revisions as Markdown table. The revision the list is currently at is marked with an arrow.
+func ToHistoryTable(revisions []Revision, maxActionLength int) string {
+	var data [][]string
+	for _, revision := range revisions {
+		marker := ""
+		if revision.Current {
+			marker = "->"
+		}
+		action := revision.Action
+		if len(action) > maxActionLength {
+			action = action[:maxActionLength-3] + "..."
+		}
+		data = append(data, []string{
+			marker,
+			strconv.Itoa(revision.ID),
+			revision.Date.Format("02.01. 15:04"),
+			revision.Author,
+			action,
+		})
+	}
+
+	writer := bytes.Buffer{}
+	writer.WriteString("```md\n")
+
+	table := tablewriter.NewWriter(&writer)
+	table.SetHeader([]string{"", "REV", "WHEN", "WHO", "CHANGE"})
+	table.SetHeaderAlignment(tablewriter.ALIGN_CENTER)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAutoWrapText(false)
+	table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
+	table.SetCenterSeparator("|")
+	table.AppendBulk(data)
+	table.Render()
+
+	writer.WriteString("```")
+
+	return writer.String()
+}
@@ -0,0 +1,55 @@
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDescribeChanges(t *testing.T) {
+	// given
+	tests := map[string]struct {
+		oldItems []PantryItem
+		newItems []PantryItem
+		expected string
+	}{
+		"no change": {
+			oldItems: []PantryItem{{ID: 1, Number: 1, Item: "eggs", Amount: 4}},
+			newItems: []PantryItem{{ID: 1, Number: 1, Item: "eggs", Amount: 4}},
+			expected: "",
+		},
+		"renumbering only": {
+			oldItems: []PantryItem{{ID: 1, Number: 1, Item: "eggs", Amount: 4}, {ID: 2, Number: 2, Item: "milk", Amount: 1}},
+			newItems: []PantryItem{{ID: 2, Number: 1, Item: "milk", Amount: 1}, {ID: 1, Number: 2, Item: "eggs", Amount: 4}},
+			expected: "",
+		},
+		"add, change and remove": {
+			oldItems: []PantryItem{{ID: 1, Number: 1, Item: "eggs", Amount: 4}, {ID: 2, Number: 2, Item: "milk", Amount: 1}},
+			newItems: []PantryItem{{ID: 1, Number: 1, Item: "eggs", Amount: 6}, {ID: 3, Number: 2, Item: "coffee", Amount: 1}},
+			expected: "added coffee; changed 6 eggs; removed milk",
+		},
+		"tick and untick": {
+			oldItems: []PantryItem{{ID: 1, Item: "eggs", Amount: 4}, {ID: 2, Item: "milk", Amount: 1, Checked: true}},
+			newItems: []PantryItem{{ID: 1, Item: "eggs", Amount: 4, Checked: true}, {ID: 2, Item: "milk", Amount: 1}},
+			expected: "ticked 4 eggs; unticked milk",
+		},
+		"remove all": {
+			oldItems: []PantryItem{
+				{ID: 1, Item: "a", Amount: 1}, {ID: 2, Item: "b", Amount: 1}, {ID: 3, Item: "c", Amount: 1},
+				{ID: 4, Item: "d", Amount: 1}, {ID: 5, Item: "e", Amount: 1}, {ID: 6, Item: "f", Amount: 1},
+				{ID: 7, Item: "g", Amount: 1},
+			},
+			newItems: []PantryItem{},
+			expected: "removed a, b, c, d, e, +2 more",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// when
+			actual := DescribeChanges(test.oldItems, test.newItems)
+
+			// then
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package model
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Meal is the dish planned for a day of a meal plan.
+type Meal struct {
+	ID       int
+	Day      time.Time // midnight of the day
+	Dish     string
+	Factor   float64 // batches of the recipe of the same name
+	Reserved []Reservation
+	Pushed   bool // missing ingredients were added to the grocery list
+	Author   string
+}
+
+// Reservation refers to an item of another list, e.g. a frozen lasagne, which is kept for a meal.
+type Reservation struct {
+	List   string
+	ItemID int
+	Item   string
+}
+
+// ToMealPlanTable renders the meals of the week starting on monday as Markdown table with a row for every day.
+// Meals whose ingredients were added to the grocery list are marked like ticked items.
+func ToMealPlanTable(meals []Meal, monday time.Time, dateFormat string) string {
+	var rows [][]string
+	for offset := range 7 {
+		day := monday.AddDate(0, 0, offset)
+		row := []string{day.Format("Mon ") + day.Format(dateFormat), "", ""}
+		for _, meal := range meals {
+			if !meal.Day.Equal(day) {
+				continue
+			}
+			row[1] = meal.Dish
+			if meal.Factor != 1 {
+				row[1] += " x" + FormatAmount(meal.Factor)
+			}
+			if meal.Pushed {
+				row[1] = CheckedMarker + row[1]
+			}
+			var reserved []string
+			for _, reservation := range meal.Reserved {
+				reserved = append(reserved, fmt.Sprintf("%s (%s)", reservation.Item, reservation.List))
+			}
+			row[2] = strings.Join(reserved, ", ")
+		}
+		rows = append(rows, row)
+	}
+
+	writer := bytes.Buffer{}
+	writer.WriteString("```md\n")
+	writer.WriteString(fmt.Sprintf("# meal plan %s\n\n", isoWeek(monday)))
+	renderTable(&writer, []string{"DAY", "DISH", "RESERVED"}, rows)
+	writer.WriteString("```")
+	return writer.String()
+}
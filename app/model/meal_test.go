@@ -0,0 +1,33 @@
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestToMealPlanTable(t *testing.T) {
+	// given
+	monday := time.Date(2026, 10, 19, 0, 0, 0, 0, time.Local)
+	meals := []Meal{
+		{Day: monday, Dish: "lasagne", Factor: 2, Pushed: true},
+		{Day: monday.AddDate(0, 0, 2), Dish: "salmon", Factor: 1, Reserved: []Reservation{{List: "tk", ItemID: 3, Item: "salmon"}, {List: "tk", ItemID: 5, Item: "peas"}}},
+	}
+
+	// when
+	actual := ToMealPlanTable(meals, monday, "02.01.")
+
+	// then
+	assert.Equal(t, "```md\n"+
+		"# meal plan 2026-W43\n\n"+
+		"|    DAY     |     DISH     |        RESERVED        |\n"+
+		"|------------|--------------|------------------------|\n"+
+		"| Mon 19.10. | ✓ lasagne x2 |                        |\n"+
+		"| Tue 20.10. |              |                        |\n"+
+		"| Wed 21.10. | salmon       | salmon (tk), peas (tk) |\n"+
+		"| Thu 22.10. |              |                        |\n"+
+		"| Fri 23.10. |              |                        |\n"+
+		"| Sat 24.10. |              |                        |\n"+
+		"| Sun 25.10. |              |                        |\n"+
+		"```", actual)
+}
@@ -0,0 +1,72 @@
+package model
+
+import (
+	"math"
+	"strings"
+)
+
+// unitConversions maps units to a smaller unit and the factor between both.
+var unitConversions = map[string]struct {
+	unit   string
+	factor float64
+}{
+	"kg": {unit: "g", factor: 1000},
+	"l":  {unit: "ml", factor: 1000},
+}
+
+// NormalizeName reduces an item name to a form in which spelling variants of the same item are equal. Case, surplus
+// whitespace and plural endings are ignored and synonyms are replaced by their canonical name.
+func NormalizeName(name string, synonyms map[string]string) string {
+	words := strings.Fields(strings.ToLower(name))
+	for index, word := range words {
+		words[index] = singular(word)
+	}
+	normalized := strings.Join(words, " ")
+
+	for synonym, canonical := range synonyms {
+		if NormalizeName(synonym, nil) == normalized {
+			return NormalizeName(canonical, nil)
+		}
+	}
+	return normalized
+}
+
+// singular strips common English plural endings. It is not meant to be correct, only to map singular and plural of a
+// word to the same string.
+func singular(word string) string {
+	switch {
+	case len(word) > 4 && strings.HasSuffix(word, "ies"):
+		return strings.TrimSuffix(word, "ies") + "y"
+	case len(word) > 4 && (strings.HasSuffix(word, "oes") || strings.HasSuffix(word, "ches") ||
+		strings.HasSuffix(word, "shes") || strings.HasSuffix(word, "xes")):
+		return strings.TrimSuffix(word, "es")
+	case len(word) > 3 && strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss"):
+		return strings.TrimSuffix(word, "s")
+	}
+	return word
+}
+
+// ConvertAmount converts an amount into another unit. Only the same unit and metric units like `kg` and `g` are
+// convertible.
+func ConvertAmount(amount float64, from, to string) (float64, bool) {
+	if from == to {
+		return amount, true
+	}
+	if conversion, ok := unitConversions[from]; ok && conversion.unit == to {
+		return roundAmount(amount * conversion.factor), true
+	}
+	if conversion, ok := unitConversions[to]; ok && conversion.unit == from {
+		return roundAmount(amount / conversion.factor), true
+	}
+	return 0, false
+}
+
+// roundAmount strips floating point noise like in 0.1 + 0.2.
+func roundAmount(amount float64) float64 {
+	return math.Round(amount*1000) / 1000
+}
+
+// AddAmount adds two amounts without floating point noise.
+func AddAmount(amount, addend float64) float64 {
+	return roundAmount(amount + addend)
+}
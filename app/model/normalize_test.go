@@ -0,0 +1,49 @@
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNormalizeName(t *testing.T) {
+	// given
+	synonyms := map[string]string{"aubergine": "eggplant", "minced meat": "ground beef"}
+
+	// then
+	for name, expected := range map[string]string{
+		"Eggs":           "egg",
+		"  egg ":         "egg",
+		"frozen  Peas":   "frozen pea",
+		"tomatoes":       "tomato",
+		"cherries":       "cherry",
+		"peaches":        "peach",
+		"glass noodles":  "glass noodle",
+		"gas":            "gas",
+		"Aubergines":     "eggplant",
+		"eggplant":       "eggplant",
+		"minced   meat":  "ground beef",
+		"minced chicken": "minced chicken",
+	} {
+		assert.Equal(t, expected, NormalizeName(name, synonyms), name)
+	}
+}
+
+func TestConvertAmount(t *testing.T) {
+	for _, test := range []struct {
+		amount   float64
+		from, to string
+		expected float64
+		ok       bool
+	}{
+		{amount: 2, expected: 2, ok: true},
+		{amount: 1.5, from: "kg", to: "g", expected: 1500, ok: true},
+		{amount: 250, from: "ml", to: "l", expected: 0.25, ok: true},
+		{amount: 2, from: "packs", to: "g"},
+		{amount: 2, from: "", to: "kg"},
+	} {
+		actual, ok := ConvertAmount(test.amount, test.from, test.to)
+		assert.Equal(t, test.ok, ok)
+		assert.Equal(t, test.expected, actual)
+	}
+	assert.Equal(t, 0.3, AddAmount(0.1, 0.2))
+}
@@ -4,21 +4,70 @@ import (
 	"bytes"
 	"fmt"
 	"github.com/olekukonko/tablewriter"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 )
 
+const (
+	NumberColumn   = "number"
+	ItemColumn     = "item"
+	QuantityColumn = "quantity"
+	AddedColumn    = "added"
+	ExpiresColumn  = "expires"
+	UnitColumn     = "unit"
+	NoteColumn     = "note"
+	CategoryColumn = "category"
+	AuthorColumn   = "by"
+
+	// noCategory is rendered for items without category, so that their empty cells are not mistaken for merged ones.
+	noCategory = "-"
+
+	// CheckedMarker precedes the names of items ticked off while shopping.
+	CheckedMarker = "✓ "
+
+	// ExpiryDateFormat renders best-before dates independent of the channel date format, which often lacks the year.
+	ExpiryDateFormat = "02.01.06"
+)
+
+var (
+	DefaultColumns = []string{NumberColumn, ItemColumn, QuantityColumn, AddedColumn}
+	columnHeaders  = map[string]string{
+		NumberColumn:   "#",
+		ItemColumn:     "ITEM",
+		QuantityColumn: "QTY",
+		AddedColumn:    "ADDED",
+		ExpiresColumn:  "EXPIRES",
+		UnitColumn:     "UNIT",
+		NoteColumn:     "NOTE",
+		CategoryColumn: "AISLE",
+		AuthorColumn:   "BY",
+	}
+)
+
 type PantryItem struct {
-	ID     int
-	Number int
-	Item   string
-	Amount int
-	Date   time.Time
+	ID       int
+	Number   int
+	Item     string
+	Amount   float64
+	Unit     string // empty for items which are just counted
+	Date     time.Time
+	Expires  time.Time // zero if the item has no best-before date
+	Note     string
+	Category string // explicitly tagged category, see CategoryOf
+	Checked  bool   // ticked off while shopping, but not yet cleared from the list
+	Author   string // user who added the item, see ItemsBy
 }
 
 func (item *PantryItem) ToString() string {
-	return fmt.Sprintf("id: `%d`; number:`%d`' item: `%s`; amount: `%d`; date: `%s`", item.ID, item.Number, item.Item, item.Amount, item.Date.Format("02.01.06"))
+	return fmt.Sprintf("id: `%d`; number:`%d`' item: `%s`; amount: `%s`; date: `%s`", item.ID, item.Number, item.Item, FormatQuantity(item.Amount, item.Unit), item.Date.Format("02.01.06"))
+}
+
+// IsColumn reports whether a table column with the given name exists.
+func IsColumn(column string) bool {
+	_, ok := columnHeaders[column]
+	return ok
 }
 
 func ToList(items []PantryItem) string {
@@ -27,12 +76,26 @@ func ToList(items []PantryItem) string {
 		if index != 0 {
 			shoppingList += "\n"
 		}
-		shoppingList += fmt.Sprintf("[%d] %d %s", index+1, item.Amount, item.Item)
+		shoppingList += fmt.Sprintf("[%d] %s %s", index+1, FormatQuantity(item.Amount, item.Unit), item.Item)
+		if !item.Expires.IsZero() {
+			shoppingList += " exp " + item.Expires.Format(ExpiryDateFormat)
+		}
+		if item.Category != "" {
+			shoppingList += " #" + item.Category
+		}
 	}
 	return shoppingList
 }
 
-func ToMarkdownTable(items []PantryItem, linebreak int, dateFormat string) string {
+// ToMarkdownTable renders items as Markdown table with the given columns, which default to DefaultColumns.
+// Item names longer than the line break are wrapped into multiple table lines. Units are part of the quantity unless
+// there is a separate unit column.
+func ToMarkdownTable(items []PantryItem, linebreak int, dateFormat string, columns ...string) string {
+	if len(columns) == 0 {
+		columns = DefaultColumns
+	}
+	hasUnitColumn := slices.Contains(columns, UnitColumn)
+
 	var data [][]string
 	for _, item := range items {
 		tableItemLines := []string{}
@@ -75,21 +138,22 @@ func ToMarkdownTable(items []PantryItem, linebreak int, dateFormat string) strin
 		}
 
 		for index, tableItemLine := range tableItemLines {
-			if index == 0 {
-				data = append(data, []string{
-					strconv.Itoa(item.Number),
-					tableItemLine,
-					strconv.Itoa(item.Amount),
-					item.Date.Format(dateFormat)},
-				)
-			} else {
-				data = append(data, []string{
-					strconv.Itoa(item.Number),
-					tableItemLine,
-					"",
-					""},
-				)
+			row := make([]string, len(columns))
+			for column, name := range columns {
+				row[column] = toCell(item, name, tableItemLine, index == 0, dateFormat, hasUnitColumn)
 			}
+			data = append(data, row)
+		}
+	}
+
+	var headers []string
+	mergedColumns := []int{0}
+	for index, column := range columns {
+		headers = append(headers, columnHeaders[column])
+		if column == NumberColumn {
+			mergedColumns[0] = index
+		} else if column == CategoryColumn {
+			mergedColumns = append(mergedColumns, index) // groups items of the same aisle
 		}
 	}
 
@@ -97,12 +161,12 @@ func ToMarkdownTable(items []PantryItem, linebreak int, dateFormat string) strin
 	writer.WriteString("```md\n")
 
 	table := tablewriter.NewWriter(&writer)
-	table.SetHeader([]string{"#", "ITEM", "QTY", "ADDED"})
+	table.SetHeader(headers)
 	table.SetHeaderAlignment(tablewriter.ALIGN_CENTER)
 	table.SetAlignment(tablewriter.ALIGN_LEFT)
 	table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
 	table.SetCenterSeparator("|")
-	table.SetAutoMergeCellsByColumnIndex([]int{0})
+	table.SetAutoMergeCellsByColumnIndex(mergedColumns)
 	table.AppendBulk(data)
 	table.Render()
 
@@ -111,39 +175,189 @@ func ToMarkdownTable(items []PantryItem, linebreak int, dateFormat string) strin
 	return writer.String()
 }
 
+// SplitMarkdownTable splits a table rendered by ToMarkdownTable into tables of at most maxLength characters. Every
+// part repeats the table header and rows of wrapped items are never split, so each part can be parsed on its own.
+func SplitMarkdownTable(table string, maxLength int) []string {
+	if len(table) <= maxLength {
+		return []string{table}
+	}
+
+	lines := strings.Split(strings.TrimSuffix(strings.TrimPrefix(table, "```md\n"), "```"), "\n")
+	if len(lines) < 2 {
+		return []string{table}
+	}
+	header := lines[0] + "\n" + lines[1] + "\n"
+	numberColumn := -1
+	for column, cell := range strings.Split(lines[0], "|") {
+		if strings.TrimSpace(cell) == columnHeaders[NumberColumn] {
+			numberColumn = column
+		}
+	}
+
+	// group continuation lines of wrapped items with their first line
+	var rows []string
+	for _, line := range lines[2:] {
+		if line == "" {
+			continue
+		}
+		cells := strings.Split(line, "|")
+		if len(rows) != 0 && numberColumn > 0 && numberColumn < len(cells) && strings.TrimSpace(cells[numberColumn]) == "" {
+			rows[len(rows)-1] += line + "\n"
+		} else {
+			rows = append(rows, line+"\n")
+		}
+	}
+
+	var parts []string
+	part := ""
+	for _, row := range rows {
+		if part != "" && len("```md\n"+header+part+row+"```") > maxLength {
+			parts = append(parts, "```md\n"+header+part+"```")
+			part = ""
+		}
+		part += row
+	}
+	return append(parts, "```md\n"+header+part+"```")
+}
+
+// toCell renders a single table cell. Continuation lines of wrapped items only repeat the number (which gets merged)
+// and carry the next chunk of the item name.
+func toCell(item PantryItem, column, itemLine string, isFirstLine bool, dateFormat string, hasUnitColumn bool) string {
+	switch column {
+	case NumberColumn:
+		return strconv.Itoa(item.Number)
+	case ItemColumn:
+		if item.Checked && isFirstLine {
+			return CheckedMarker + itemLine
+		}
+		return itemLine
+	case CategoryColumn:
+		if item.Category == "" {
+			return noCategory
+		}
+		return item.Category
+	}
+	if !isFirstLine {
+		return ""
+	}
+	switch column {
+	case QuantityColumn:
+		if hasUnitColumn {
+			return FormatAmount(item.Amount)
+		}
+		return FormatQuantity(item.Amount, item.Unit)
+	case UnitColumn:
+		return item.Unit
+	case NoteColumn:
+		return item.Note
+	case AuthorColumn:
+		return item.Author
+	case AddedColumn:
+		return item.Date.Format(dateFormat)
+	case ExpiresColumn:
+		if item.Expires.IsZero() {
+			return ""
+		}
+		return item.Expires.Format(ExpiryDateFormat)
+	}
+	return ""
+}
+
+// FromMarkdownTable parses a table rendered by ToMarkdownTable. Columns are detected by their headers, so tables
+// with any column layout can be read.
 func FromMarkdownTable(table string, dateFormat string) []PantryItem {
 	var result []PantryItem
 	splitTable := strings.Split(table, "\n")
+	columns := map[string]int{}
 
 	for index, item := range splitTable {
+		if index == 1 {
+			for column, header := range strings.Split(item, "|") {
+				for name, columnHeader := range columnHeaders {
+					if strings.TrimSpace(header) == columnHeader {
+						columns[name] = column
+					}
+				}
+			}
+		}
 		if index <= 2 || index == len(splitTable)-1 {
 			continue
 		}
 
 		splitItem := strings.Split(item, "|")
-		number, err := strconv.Atoi(strings.TrimSpace(splitItem[1]))
+		cell := func(column string) (string, bool) {
+			position, ok := columns[column]
+			if !ok || position >= len(splitItem) {
+				return "", false
+			}
+			return strings.TrimSpace(splitItem[position]), true
+		}
+		rawNumber, hasNumber := cell(NumberColumn)
+		itemLine, hasItem := cell(ItemColumn)
+		if !hasNumber || !hasItem {
+			// not a table row -> skip instead of mangling the list
+			continue
+		}
+
+		number, err := strconv.Atoi(rawNumber)
 		if err != nil {
+			if len(result) == 0 {
+				continue
+			}
 			// overwriting last item -> assuming it is a multi-line item because it does not have a number
 			lastItem := result[len(result)-1]
 			if strings.HasSuffix(lastItem.Item, "-") {
-				lastItem.Item = strings.TrimSuffix(lastItem.Item, "-") + strings.TrimSpace(splitItem[2])
+				lastItem.Item = strings.TrimSuffix(lastItem.Item, "-") + itemLine
 			} else {
-				lastItem.Item += " " + strings.TrimSpace(splitItem[2])
+				lastItem.Item += " " + itemLine
 			}
 			result[len(result)-1] = lastItem
 			continue
 		}
-		amount, _ := strconv.Atoi(strings.TrimSpace(splitItem[3]))
-		date, _ := time.Parse(dateFormat, strings.TrimSpace(splitItem[4]))
-		if date.Year() <= 0 {
-			date = time.Date(time.Now().Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.Local)
+
+		amount, unit := 1.0, ""
+		if rawQuantity, ok := cell(QuantityColumn); ok {
+			amount, unit, _ = ParseQuantity(rawQuantity)
+		}
+		if rawUnit, ok := cell(UnitColumn); ok {
+			unit = NormalizeUnit(rawUnit)
+		}
+		date := time.Now().Truncate(time.Minute)
+		if rawDate, ok := cell(AddedColumn); ok {
+			date, _ = time.Parse(dateFormat, rawDate)
+			if date.Year() <= 0 {
+				date = time.Date(time.Now().Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.Local)
+			}
+		}
+
+		var expires time.Time
+		if rawExpires, ok := cell(ExpiresColumn); ok && rawExpires != "" {
+			expires, _ = time.ParseInLocation(ExpiryDateFormat, rawExpires, time.Local)
+		}
+
+		checked := strings.HasPrefix(itemLine, CheckedMarker)
+		itemLine = strings.TrimPrefix(itemLine, CheckedMarker)
+
+		note, _ := cell(NoteColumn)
+		author, _ := cell(AuthorColumn)
+		category, _ := cell(CategoryColumn)
+		if category == "" && len(result) != 0 {
+			category = result[len(result)-1].Category // merged cell of the same aisle
+		} else if category == noCategory {
+			category = ""
 		}
 
 		result = append(result, PantryItem{
-			Number: number,
-			Item:   strings.TrimSpace(splitItem[2]),
-			Amount: amount,
-			Date:   date,
+			Number:   number,
+			Item:     itemLine,
+			Amount:   amount,
+			Unit:     unit,
+			Date:     date,
+			Expires:  expires,
+			Note:     note,
+			Category: category,
+			Checked:  checked,
+			Author:   author,
 		})
 	}
 	return result
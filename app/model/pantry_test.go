@@ -2,6 +2,7 @@ package model
 
 import (
 	"github.com/stretchr/testify/assert"
+	"strings"
 	"testing"
 	"time"
 )
@@ -14,7 +15,7 @@ func TestToMarkdownTable(t *testing.T) {
 	}{
 		"no conversion": {
 			items: []PantryItem{
-				{0, 1, "12345 12345 12345", 1, time.Date(2023, 12, 27, 0, 0, 0, 0, time.Local)},
+				{Number: 1, Item: "12345 12345 12345", Amount: 1, Date: time.Date(2023, 12, 27, 0, 0, 0, 0, time.Local)},
 			},
 			expected: "```md\n" +
 				"| # |       ITEM        | QTY |  ADDED   |\n" +
@@ -24,7 +25,7 @@ func TestToMarkdownTable(t *testing.T) {
 		},
 		"simple conversion": {
 			items: []PantryItem{
-				{0, 1, "12345 12345 12345 12345 12345", 1, time.Date(2023, 12, 27, 0, 0, 0, 0, time.Local)},
+				{Number: 1, Item: "12345 12345 12345 12345 12345", Amount: 1, Date: time.Date(2023, 12, 27, 0, 0, 0, 0, time.Local)},
 			},
 			expected: "```md\n" +
 				"| # |       ITEM        | QTY |  ADDED   |\n" +
@@ -35,7 +36,7 @@ func TestToMarkdownTable(t *testing.T) {
 		},
 		"single too large item": {
 			items: []PantryItem{
-				{0, 1, "1234512345123451234512345", 1, time.Date(2023, 12, 27, 0, 0, 0, 0, time.Local)},
+				{Number: 1, Item: "1234512345123451234512345", Amount: 1, Date: time.Date(2023, 12, 27, 0, 0, 0, 0, time.Local)},
 			},
 			expected: "```md\n" +
 				"| # |         ITEM         | QTY |  ADDED   |\n" +
@@ -46,7 +47,7 @@ func TestToMarkdownTable(t *testing.T) {
 		},
 		"too large item": {
 			items: []PantryItem{
-				{0, 1, "12345 1234512345123451234512345", 1, time.Date(2023, 12, 27, 0, 0, 0, 0, time.Local)},
+				{Number: 1, Item: "12345 1234512345123451234512345", Amount: 1, Date: time.Date(2023, 12, 27, 0, 0, 0, 0, time.Local)},
 			},
 			expected: "```md\n" +
 				"| # |         ITEM         | QTY |  ADDED   |\n" +
@@ -151,6 +152,24 @@ func TestFromMarkdownTable(t *testing.T) {
 				},
 			},
 		},
+		"corrupted message": {
+			table: "```md\n" +
+				"| # |  ITEM  | QTY | ADDED  |\n" +
+				"|---|--------|-----|--------|\n" +
+				"|   | orphan |     |        |\n" +
+				"some text\n" +
+				"| 1 | eggs   | 4   | 24.12. |\n" +
+				"```",
+			expected: []PantryItem{
+				{
+					ID:     0,
+					Number: 1,
+					Item:   "eggs",
+					Amount: 4,
+					Date:   time.Date(time.Now().Year(), 12, 24, 0, 0, 0, 0, time.Local),
+				},
+			},
+		},
 	}
 
 	for name, test := range tests {
@@ -163,3 +182,212 @@ func TestFromMarkdownTable(t *testing.T) {
 		})
 	}
 }
+
+func TestMarkdownTableWithColumns(t *testing.T) {
+	// given
+	items := []PantryItem{
+		{Number: 1, Item: "eggs", Amount: 4, Date: time.Date(2023, 12, 27, 0, 0, 0, 0, time.Local)},
+		{Number: 2, Item: "coffee and more coffee", Amount: 2, Date: time.Date(2023, 12, 27, 0, 0, 0, 0, time.Local)},
+	}
+
+	// when
+	table := ToMarkdownTable(items, 20, "02.01.06", ItemColumn, NumberColumn)
+
+	// then
+	assert.EqualValues(t, "```md\n"+
+		"|      ITEM       | # |\n"+
+		"|-----------------|---|\n"+
+		"| eggs            | 1 |\n"+
+		"| coffee and more | 2 |\n"+
+		"| coffee          |   |\n"+
+		"```", table)
+
+	// and
+	actual := FromMarkdownTable(table, "02.01.06")
+	assert.Len(t, actual, 2)
+	assert.Equal(t, "coffee and more coffee", actual[1].Item)
+	assert.Equal(t, 1.0, actual[1].Amount)
+}
+
+func TestMarkdownTableWithExpiry(t *testing.T) {
+	// given
+	items := []PantryItem{
+		{Number: 1, Item: "salmon", Amount: 2, Date: time.Date(time.Now().Year(), 12, 27, 0, 0, 0, 0, time.Local), Expires: time.Date(2027, 3, 31, 0, 0, 0, 0, time.Local)},
+		{Number: 2, Item: "peas", Amount: 1, Date: time.Date(time.Now().Year(), 12, 27, 0, 0, 0, 0, time.Local)},
+	}
+
+	// when
+	table := ToMarkdownTable(items, 20, "02.01.", NumberColumn, ItemColumn, QuantityColumn, ExpiresColumn)
+
+	// then
+	assert.EqualValues(t, "```md\n"+
+		"| # |  ITEM  | QTY | EXPIRES  |\n"+
+		"|---|--------|-----|----------|\n"+
+		"| 1 | salmon | 2   | 31.03.27 |\n"+
+		"| 2 | peas   | 1   |          |\n"+
+		"```", table)
+
+	// and
+	actual := FromMarkdownTable(ToMarkdownTable(items, 20, "02.01."), "02.01.")
+	assert.True(t, actual[0].Expires.IsZero())
+	actual = FromMarkdownTable(table, "02.01.")
+	assert.Equal(t, items[0].Expires, actual[0].Expires)
+	assert.True(t, actual[1].Expires.IsZero())
+	assert.Equal(t, "[1] 2 salmon exp 31.03.27\n[2] 1 peas", ToList(items))
+}
+
+func TestMarkdownTableWithCheckedItems(t *testing.T) {
+	// given
+	items := []PantryItem{
+		{Number: 1, Item: "eggs", Amount: 4, Checked: true},
+		{Number: 2, Item: "a very long oat milk", Amount: 1, Checked: true},
+		{Number: 3, Item: "coffee", Amount: 1},
+	}
+
+	// when
+	table := ToMarkdownTable(items, 15, "02.01.", NumberColumn, ItemColumn, QuantityColumn)
+
+	// then
+	assert.EqualValues(t, "```md\n"+
+		"| # |       ITEM        | QTY |\n"+
+		"|---|-------------------|-----|\n"+
+		"| 1 | ✓ eggs            | 4   |\n"+
+		"| 2 | ✓ a very long oat | 1   |\n"+
+		"|   | milk              |     |\n"+
+		"| 3 | coffee            | 1   |\n"+
+		"```", table)
+
+	// and
+	actual := FromMarkdownTable(table, "02.01.")
+	assert.Equal(t, []bool{true, true, false}, []bool{actual[0].Checked, actual[1].Checked, actual[2].Checked})
+	assert.Equal(t, "a very long oat milk", actual[1].Item)
+}
+
+func TestMarkdownTableWithAuthors(t *testing.T) {
+	// given
+	items := []PantryItem{
+		{Number: 1, Item: "the blue sauce", Amount: 1, Author: "mari"},
+		{Number: 2, Item: "coffee", Amount: 1},
+	}
+
+	// when
+	table := ToMarkdownTable(items, 10, "02.01.", NumberColumn, ItemColumn, AuthorColumn)
+
+	// then
+	assert.EqualValues(t, "```md\n"+
+		"| # |   ITEM   |  BY  |\n"+
+		"|---|----------|------|\n"+
+		"| 1 | the blue | mari |\n"+
+		"|   | sauce    |      |\n"+
+		"| 2 | coffee   |      |\n"+
+		"```", table)
+
+	// and
+	actual := FromMarkdownTable(table, "02.01.")
+	assert.Equal(t, []string{"mari", ""}, []string{actual[0].Author, actual[1].Author})
+}
+
+func TestMarkdownTableWithUnits(t *testing.T) {
+	// given
+	date := time.Date(time.Now().Year(), 12, 27, 0, 0, 0, 0, time.Local)
+	items := []PantryItem{
+		{Number: 1, Item: "flour", Amount: 500, Unit: "g", Date: date},
+		{Number: 2, Item: "milk", Amount: 1.5, Unit: "l", Date: date},
+		{Number: 3, Item: "eggs", Amount: 6, Date: date},
+	}
+
+	// when
+	combined := ToMarkdownTable(items, 20, "02.01.", NumberColumn, ItemColumn, QuantityColumn)
+	separate := ToMarkdownTable(items, 20, "02.01.", NumberColumn, ItemColumn, QuantityColumn, UnitColumn)
+
+	// then
+	assert.EqualValues(t, "```md\n"+
+		"| # | ITEM  |  QTY  |\n"+
+		"|---|-------|-------|\n"+
+		"| 1 | flour | 500 g |\n"+
+		"| 2 | milk  | 1.5 l |\n"+
+		"| 3 | eggs  | 6     |\n"+
+		"```", combined)
+	assert.EqualValues(t, "```md\n"+
+		"| # | ITEM  | QTY | UNIT |\n"+
+		"|---|-------|-----|------|\n"+
+		"| 1 | flour | 500 | g    |\n"+
+		"| 2 | milk  | 1.5 | l    |\n"+
+		"| 3 | eggs  | 6   |      |\n"+
+		"```", separate)
+
+	// and
+	for _, table := range []string{combined, separate} {
+		for index, item := range FromMarkdownTable(table, "02.01.") {
+			assert.Equal(t, items[index].Amount, item.Amount)
+			assert.Equal(t, items[index].Unit, item.Unit)
+		}
+	}
+	assert.Equal(t, "[1] 500 g flour\n[2] 1.5 l milk\n[3] 6 eggs", ToList(items))
+}
+
+func TestMarkdownTableWithCategories(t *testing.T) {
+	// given
+	date := time.Date(time.Now().Year(), 12, 27, 0, 0, 0, 0, time.Local)
+	items := []PantryItem{
+		{Number: 1, Item: "apples", Amount: 6, Date: date, Category: "produce"},
+		{Number: 2, Item: "a very long salad name", Amount: 1, Date: date, Category: "produce"},
+		{Number: 3, Item: "milk", Amount: 1, Date: date, Category: "dairy"},
+		{Number: 4, Item: "batteries", Amount: 1, Date: date},
+	}
+
+	// when
+	table := ToMarkdownTable(items, 15, "02.01.", CategoryColumn, NumberColumn, ItemColumn)
+
+	// then
+	assert.EqualValues(t, "```md\n"+
+		"|  AISLE  | # |    ITEM     |\n"+
+		"|---------|---|-------------|\n"+
+		"| produce | 1 | apples      |\n"+
+		"|         | 2 | a very long |\n"+
+		"|         |   | salad name  |\n"+
+		"| dairy   | 3 | milk        |\n"+
+		"| -       | 4 | batteries   |\n"+
+		"```", table)
+
+	// and
+	var categories []string
+	for _, item := range FromMarkdownTable(table, "02.01.") {
+		categories = append(categories, item.Category)
+	}
+	assert.Equal(t, []string{"produce", "produce", "dairy", ""}, categories)
+}
+
+func TestSplitMarkdownTable(t *testing.T) {
+	// given
+	var items []PantryItem
+	for i := 1; i <= 60; i++ {
+		items = append(items, PantryItem{Number: i, Item: "a rather long item name which wraps", Amount: float64(i), Date: time.Date(time.Now().Year(), 12, 27, 0, 0, 0, 0, time.Local)})
+	}
+	table := ToMarkdownTable(items, 20, "02.01.")
+
+	// when
+	parts := SplitMarkdownTable(table, 2000)
+
+	// then
+	assert.Greater(t, len(parts), 1)
+	var actual []PantryItem
+	for _, part := range parts {
+		assert.LessOrEqual(t, len(part), 2000)
+		assert.True(t, strings.HasPrefix(part, table[:strings.Index(table, "\n|-")]))
+		assert.True(t, strings.HasSuffix(part, "|\n```"))
+		actual = append(actual, FromMarkdownTable(part, "02.01.")...)
+	}
+	assert.EqualValues(t, items, actual)
+}
+
+func TestSplitShortMarkdownTable(t *testing.T) {
+	// given
+	table := ToMarkdownTable([]PantryItem{{Number: 1, Item: "eggs", Amount: 4}}, 20, "02.01.06")
+
+	// when
+	parts := SplitMarkdownTable(table, 2000)
+
+	// then
+	assert.Equal(t, []string{table}, parts)
+}
@@ -0,0 +1,53 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// QuantityPattern matches a quantity like `3`, `1.5 l`, `500g` or the multiplier `2x` within a line of input.
+const QuantityPattern = `\d+(?:[.,]\d+)?\s*(?:kg|g|ml|l|pcs|pc|packs|pack|x)?`
+
+var (
+	Units         = []string{"g", "kg", "ml", "l", "pcs", "packs"}
+	quantityRegex = regexp.MustCompile(`(?i)^(\d+(?:[.,]\d+)?)\s*(kg|g|ml|l|pcs|pc|packs|pack|x)?$`)
+	unitAliases   = map[string]string{"pc": "pcs", "pack": "packs", "x": ""}
+)
+
+// ParseQuantity splits a quantity like `1,5 l` into amount and unit. Units are normalized, so `2 pack` has the unit
+// `packs` and the multiplier `2x` has no unit at all.
+func ParseQuantity(value string) (float64, string, error) {
+	match := quantityRegex.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil {
+		return 0, "", fmt.Errorf("invalid quantity `%s`", value)
+	}
+	amount, err := strconv.ParseFloat(strings.Replace(match[1], ",", ".", 1), 64)
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid quantity `%s`", value)
+	}
+	return amount, NormalizeUnit(match[2]), nil
+}
+
+// NormalizeUnit maps aliases like `pack` or `L` to the units listed in Units.
+func NormalizeUnit(unit string) string {
+	unit = strings.ToLower(strings.TrimSpace(unit))
+	if alias, ok := unitAliases[unit]; ok {
+		return alias
+	}
+	return unit
+}
+
+// FormatAmount renders an amount without trailing zeros, e.g. `2` or `1.5`.
+func FormatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', -1, 64)
+}
+
+// FormatQuantity renders amount and unit, e.g. `500 g` or just `3` for items without unit.
+func FormatQuantity(amount float64, unit string) string {
+	if unit == "" {
+		return FormatAmount(amount)
+	}
+	return FormatAmount(amount) + " " + unit
+}
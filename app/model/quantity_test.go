@@ -0,0 +1,48 @@
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseQuantity(t *testing.T) {
+	// given
+	tests := map[string]struct {
+		amount float64
+		unit   string
+	}{
+		"3":       {amount: 3},
+		"2x":      {amount: 2},
+		"500g":    {amount: 500, unit: "g"},
+		"1.5 l":   {amount: 1.5, unit: "l"},
+		"1,5L":    {amount: 1.5, unit: "l"},
+		"0.25 kg": {amount: 0.25, unit: "kg"},
+		"2 pack":  {amount: 2, unit: "packs"},
+		"6 pc":    {amount: 6, unit: "pcs"},
+	}
+
+	for value, test := range tests {
+		t.Run(value, func(t *testing.T) {
+			// when
+			amount, unit, err := ParseQuantity(value)
+
+			// then
+			assert.NoError(t, err)
+			assert.Equal(t, test.amount, amount)
+			assert.Equal(t, test.unit, unit)
+		})
+	}
+}
+
+func TestParseInvalidQuantity(t *testing.T) {
+	for _, value := range []string{"", "eggs", "2 cups", "1.5.3 l"} {
+		_, _, err := ParseQuantity(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestFormatQuantity(t *testing.T) {
+	assert.Equal(t, "3", FormatQuantity(3, ""))
+	assert.Equal(t, "1.5 l", FormatQuantity(1.5, "l"))
+	assert.Equal(t, "500 g", FormatQuantity(500, "g"))
+}
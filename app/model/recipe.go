@@ -0,0 +1,22 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Recipe lists the ingredients of a dish for a single batch.
+type Recipe struct {
+	Name        string
+	Ingredients []string // ingredients as typed in the channel, e.g. `500g minced meat`
+	Author      string   // empty for recipes from the config
+}
+
+// ToRecipeList renders recipes with their ingredients, one recipe per line.
+func ToRecipeList(recipes []Recipe) string {
+	var lines []string
+	for _, recipe := range recipes {
+		lines = append(lines, fmt.Sprintf("**%s**: %s", recipe.Name, strings.Join(recipe.Ingredients, "; ")))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+const (
+	ClearedReason   = "cleared"   // taken off the list, e.g. by its number after buying or using it up
+	RemovedReason   = "removed"   // corrected away, e.g. a typo removed right after adding it or deleted in the edit modal
+	MovedReason     = "moved"     // moved to another list, unless moved out of a shopping list
+	PurchasedReason = "purchased" // ticked off and cleared at the end of a shopping trip or moved out of a shopping list
+)
+
+// Removal records an item leaving a list.
+type Removal struct {
+	ID       int
+	List     string
+	Item     string
+	Amount   float64
+	Unit     string
+	Category string
+	Author   string
+	Reason   string
+	Added    time.Time // when the item was added to the list
+	Removed  time.Time
+}
+
+// Days returns how long the item was on the list.
+func (removal Removal) Days() float64 {
+	return removal.Removed.Sub(removal.Added).Hours() / 24
+}
+
+// IsConsumption reports whether the item was bought or used up, as opposed to corrected away or moved.
+func (removal Removal) IsConsumption() bool {
+	return removal.Reason == PurchasedReason || removal.Reason == ClearedReason
+}
@@ -0,0 +1,48 @@
+package model
+
+import (
+	"bytes"
+	"github.com/olekukonko/tablewriter"
+	"strconv"
+	"time"
+)
+
+// Schedule adds an item to a list again and again, e.g. `milk 2` every `monday`.
+type Schedule struct {
+	ID      int
+	Item    string // item as typed in the channel, including its quantity
+	Every   string // recurrence like `monday`, `3 weeks` or `day at 18:00`
+	Next    time.Time
+	Paused  bool
+	Author  string
+	Created time.Time
+}
+
+// ToScheduleTable renders the schedules of a list as Markdown table.
+func ToScheduleTable(schedules []Schedule) string {
+	var data [][]string
+	for _, schedule := range schedules {
+		next := schedule.Next.Format("02.01. 15:04")
+		if schedule.Paused {
+			next = "paused"
+		}
+		data = append(data, []string{strconv.Itoa(schedule.ID), schedule.Item, schedule.Every, next})
+	}
+
+	writer := bytes.Buffer{}
+	writer.WriteString("```md\n")
+
+	table := tablewriter.NewWriter(&writer)
+	table.SetHeader([]string{"ID", "ITEM", "EVERY", "NEXT"})
+	table.SetHeaderAlignment(tablewriter.ALIGN_CENTER)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAutoWrapText(false)
+	table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
+	table.SetCenterSeparator("|")
+	table.AppendBulk(data)
+	table.Render()
+
+	writer.WriteString("```")
+
+	return writer.String()
+}
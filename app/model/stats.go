@@ -0,0 +1,128 @@
+package model
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/olekukonko/tablewriter"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	statsTopItems = 10
+	statsWeeks    = 8
+)
+
+// Stats summarizes how items leave a list, e.g. which groceries are bought most or how long food stays in the freezer.
+// Only bought or used up items are counted, corrections and moves are reported per reason.
+type Stats struct {
+	List        string
+	Consumed    int            // items bought or used up
+	Reasons     map[string]int // all removals per reason
+	AverageDays float64        // average time consumed items stayed on the list
+	TopItems    []ItemCount    // most frequently consumed items, most frequent first
+	Weeks       []WeekCount    // consumed items of the last weeks, oldest first
+}
+
+type ItemCount struct {
+	Item  string
+	Count int
+}
+
+type WeekCount struct {
+	Week  string // ISO week like `2024-W05`
+	Count int
+}
+
+// CalculateStats aggregates the removals of a list. Items are counted by their normalized name, so `egg` and `eggs`
+// are the same item.
+func CalculateStats(list string, removals []Removal, synonyms map[string]string, now time.Time) Stats {
+	stats := Stats{List: list, Reasons: map[string]int{}, TopItems: []ItemCount{}, Weeks: []WeekCount{}}
+
+	var days float64
+	counts := map[string]int{}
+	weeks := map[string]int{}
+	for _, removal := range removals {
+		stats.Reasons[removal.Reason]++
+		if !removal.IsConsumption() {
+			continue
+		}
+		stats.Consumed++
+		days += removal.Days()
+		counts[NormalizeName(removal.Item, synonyms)]++
+		weeks[isoWeek(removal.Removed)]++
+	}
+	if stats.Consumed != 0 {
+		stats.AverageDays = math.Round(days/float64(stats.Consumed)*10) / 10
+	}
+
+	for item, count := range counts {
+		stats.TopItems = append(stats.TopItems, ItemCount{Item: item, Count: count})
+	}
+	slices.SortFunc(stats.TopItems, func(a, b ItemCount) int {
+		if a.Count != b.Count {
+			return b.Count - a.Count
+		}
+		return strings.Compare(a.Item, b.Item)
+	})
+	stats.TopItems = stats.TopItems[:min(len(stats.TopItems), statsTopItems)]
+
+	for week := statsWeeks - 1; week >= 0; week-- {
+		label := isoWeek(now.AddDate(0, 0, -7*week))
+		stats.Weeks = append(stats.Weeks, WeekCount{Week: label, Count: weeks[label]})
+	}
+	return stats
+}
+
+func isoWeek(date time.Time) string {
+	year, week := date.ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
+
+// ToStatsTable renders the stats of a list as Markdown tables of the top items and the consumed items per week.
+func ToStatsTable(stats Stats) string {
+	writer := bytes.Buffer{}
+	writer.WriteString("```md\n")
+	writer.WriteString(fmt.Sprintf("# %s: %d items bought or used up, kept %s days on average\n", stats.List, stats.Consumed, FormatAmount(stats.AverageDays)))
+	var others []string
+	for _, reason := range []string{RemovedReason, MovedReason} {
+		if count := stats.Reasons[reason]; count != 0 {
+			others = append(others, fmt.Sprintf("%d %s", count, reason))
+		}
+	}
+	if len(others) != 0 {
+		writer.WriteString(fmt.Sprintf("# not counted: %s\n", strings.Join(others, ", ")))
+	}
+	writer.WriteString("\n")
+
+	var topItems [][]string
+	for index, item := range stats.TopItems {
+		topItems = append(topItems, []string{strconv.Itoa(index + 1), item.Item, strconv.Itoa(item.Count)})
+	}
+	renderTable(&writer, []string{"#", "ITEM", "COUNT"}, topItems)
+	writer.WriteString("\n")
+
+	var weeks [][]string
+	for _, week := range stats.Weeks {
+		weeks = append(weeks, []string{week.Week, strconv.Itoa(week.Count)})
+	}
+	renderTable(&writer, []string{"WEEK", "COUNT"}, weeks)
+
+	writer.WriteString("```")
+	return writer.String()
+}
+
+func renderTable(writer *bytes.Buffer, headers []string, data [][]string) {
+	table := tablewriter.NewWriter(writer)
+	table.SetHeader(headers)
+	table.SetHeaderAlignment(tablewriter.ALIGN_CENTER)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAutoWrapText(false)
+	table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
+	table.SetCenterSeparator("|")
+	table.AppendBulk(data)
+	table.Render()
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCalculateStats(t *testing.T) {
+	// given
+	now := time.Date(2024, 2, 1, 12, 0, 0, 0, time.Local) // 2024-W05
+	removals := []Removal{
+		{Item: "Coffee", Reason: PurchasedReason, Added: now.AddDate(0, 0, -20), Removed: now.AddDate(0, 0, -14)},
+		{Item: "eggs", Reason: ClearedReason, Added: now.AddDate(0, 0, -8), Removed: now.AddDate(0, 0, -7)},
+		{Item: "coffee", Reason: PurchasedReason, Added: now.AddDate(0, 0, -7), Removed: now.AddDate(0, 0, -2)},
+		{Item: "aubergine", Reason: ClearedReason, Added: now.AddDate(0, 0, -1), Removed: now},
+		{Item: "old salmon", Reason: ClearedReason, Added: now.AddDate(-1, 0, 0), Removed: now.AddDate(0, -6, 0)},
+		{Item: "cofee", Reason: RemovedReason, Added: now.Add(-time.Minute), Removed: now},
+		{Item: "eggs", Reason: MovedReason, Added: now.AddDate(0, 0, -3), Removed: now},
+	}
+
+	// when
+	stats := CalculateStats("groceries", removals, map[string]string{"aubergine": "eggplant"}, now)
+
+	// then
+	assert.Equal(t, 5, stats.Consumed)
+	assert.Equal(t, map[string]int{"purchased": 2, "cleared": 3, "removed": 1, "moved": 1}, stats.Reasons)
+	assert.Equal(t, 38.8, stats.AverageDays)
+	assert.Equal(t, []ItemCount{{"coffee", 2}, {"egg", 1}, {"eggplant", 1}, {"old salmon", 1}}, stats.TopItems)
+	assert.Equal(t, []WeekCount{
+		{"2023-W50", 0}, {"2023-W51", 0}, {"2023-W52", 0}, {"2024-W01", 0},
+		{"2024-W02", 0}, {"2024-W03", 1}, {"2024-W04", 1}, {"2024-W05", 2},
+	}, stats.Weeks)
+
+	// and
+	table := ToStatsTable(stats)
+	assert.True(t, strings.HasPrefix(table, "```md\n# groceries: 5 items bought or used up, kept 38.8 days on average\n# not counted: 1 removed, 1 moved\n\n"))
+	assert.Contains(t, table, "| 1 | coffee     | 2     |")
+	assert.Contains(t, table, "| 2024-W05 | 2     |")
+}
@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// Suggestion is an item which is likely due to be bought again, judging by the intervals between past purchases.
+type Suggestion struct {
+	Item     string
+	Amount   float64 // amount of the last purchase
+	Unit     string
+	Interval float64 // typical days between two purchases
+	Last     time.Time
+}
@@ -3,24 +3,57 @@ package model
 import (
 	"database/sql"
 	"github.com/bwmarrin/discordgo"
+	"time"
 )
 
 type DiscordBot interface {
 	Ready(*discordgo.Session, *discordgo.Ready)
 	MessageDispatch(*discordgo.Session, *discordgo.MessageCreate)
 	InteractionDispatch(*discordgo.Session, *discordgo.InteractionCreate)
+	GetListHandlers() map[string]ListHandler
+	IsReady() bool
 	CloseSession()
 }
 
+// ListHandler gives access to a list outside of Discord events. Changes are stored, recorded in the list history and
+// published to the list channel.
+type ListHandler interface {
+	GetItems() ([]PantryItem, error)
+	ChangeItems(string, func([]PantryItem) ([]PantryItem, error)) ([]PantryItem, error)
+	MoveItems(string, []int, ListHandler) ([]PantryItem, error)
+	GetRemovals(time.Time) ([]Removal, error)
+}
+
+// ListRegistry resolves all lists by name, so that handlers can change other lists.
+type ListRegistry interface {
+	GetListHandlers() map[string]ListHandler
+}
+
+// ChatSession is the part of a Discord session the handlers use. It is satisfied by *discordgo.Session, while tests
+// run the handlers against an in-memory channel.
+type ChatSession interface {
+	ChannelMessages(channelID string, limit int, beforeID, afterID, aroundID string, options ...discordgo.RequestOption) ([]*discordgo.Message, error)
+	ChannelMessageSendComplex(channelID string, data *discordgo.MessageSend, options ...discordgo.RequestOption) (*discordgo.Message, error)
+	ChannelMessageEditComplex(data *discordgo.MessageEdit, options ...discordgo.RequestOption) (*discordgo.Message, error)
+	ChannelMessageDelete(channelID, messageID string, options ...discordgo.RequestOption) error
+	ChannelMessagesBulkDelete(channelID string, messageIDs []string, options ...discordgo.RequestOption) error
+	InteractionRespond(interaction *discordgo.Interaction, response *discordgo.InteractionResponse, options ...discordgo.RequestOption) error
+	FollowupMessageCreate(interaction *discordgo.Interaction, wait bool, data *discordgo.WebhookParams, options ...discordgo.RequestOption) (*discordgo.Message, error)
+}
+
+// BotHandler handles the Discord events of a channel. Handlers of list channels are ListHandlers as well.
 type BotHandler interface {
-	ReadyEvent(*discordgo.Session, *discordgo.Ready)
-	MessageEvent(*discordgo.Session, *discordgo.MessageCreate)
-	MessageComponentInteractionEvent(*discordgo.Session, *discordgo.InteractionCreate)
-	ModalSubmitInteractionEvent(*discordgo.Session, *discordgo.InteractionCreate)
+	ReadyEvent(ChatSession, *discordgo.Ready)
+	MessageEvent(ChatSession, *discordgo.MessageCreate)
+	MessageComponentInteractionEvent(ChatSession, *discordgo.InteractionCreate)
+	ModalSubmitInteractionEvent(ChatSession, *discordgo.InteractionCreate)
+	ApplicationCommandInteractionEvent(ChatSession, *discordgo.InteractionCreate)
+	ApplicationCommandAutocompleteInteractionEvent(ChatSession, *discordgo.InteractionCreate)
 }
 
 type DatabaseClient interface {
 	GetDatabaseConnection() *sql.DB
+	Ping() error
 	CloseDatabaseConnection()
 }
 
@@ -29,4 +62,37 @@ type PantryClient interface {
 	UpdateItem(PantryItem) error
 	RemoveItem(int) error
 	GetItems() ([]PantryItem, error)
+	ReplaceItems([]PantryItem) ([]PantryItem, error)
+	MoveItems([]PantryItem, PantryClient, []PantryItem) ([]PantryItem, []PantryItem, error)
+}
+
+type HistoryClient interface {
+	AddRevision(Revision) (int, error)
+	Undo() (*Revision, error)
+	Redo() (*Revision, error)
+	GetRevisions(int) ([]Revision, error)
+}
+
+type RemovalClient interface {
+	AddRemovals([]Removal) error
+	GetRemovals(time.Time) ([]Removal, error)
+}
+
+type ScheduleClient interface {
+	AddSchedule(Schedule) (int, error)
+	GetSchedules() ([]Schedule, error)
+	UpdateSchedule(Schedule) error
+	RemoveSchedule(int) error
+}
+
+type RecipeClient interface {
+	SaveRecipe(Recipe) error
+	GetRecipes() ([]Recipe, error)
+	RemoveRecipe(string) error
+}
+
+type MealClient interface {
+	SaveMeal(Meal) (int, error)
+	GetMeals(time.Time, time.Time) ([]Meal, error)
+	RemoveMeal(time.Time) error
 }
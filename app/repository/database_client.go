@@ -7,6 +7,8 @@ import (
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/rs/zerolog/log"
 	"os"
+	"path/filepath"
+	"slices"
 )
 
 type DatabaseClient struct {
@@ -14,8 +16,12 @@ type DatabaseClient struct {
 }
 
 func NewDatabaseClient() model.DatabaseClient {
+	sqlite := initSqliteConnection()
+	if err := runMigrations(sqlite, pantryTables()); err != nil {
+		log.Fatal().Err(err).Msg("Could not migrate database schema")
+	}
 	return &DatabaseClient{
-		sqlite: initSqliteConnection(),
+		sqlite: sqlite,
 	}
 }
 
@@ -23,7 +29,7 @@ func initSqliteConnection() *sql.DB {
 	_, err := os.Stat(config.Config.Database.Sqlite)
 	if os.IsNotExist(err) {
 		log.Info().Msg("No sqlite file present -> creating one")
-		_ = os.MkdirAll(config.Config.Database.Sqlite, os.ModePerm)
+		_ = os.MkdirAll(filepath.Dir(config.Config.Database.Sqlite), os.ModePerm)
 		if file, err := os.Create(config.Config.Database.Sqlite); err != nil {
 			log.Fatal().Err(err).Msg("Could not create sqlite file")
 		} else {
@@ -46,10 +52,26 @@ func initSqliteConnection() *sql.DB {
 	return sqlite
 }
 
+// pantryTables lists the tables of all configured list channels. Each of them receives the pantry migrations.
+func pantryTables() []string {
+	var tables []string
+	for _, channel := range config.Config.Discord.Channels {
+		if channel.Type == config.ListChannelType && !slices.Contains(tables, channel.Table) {
+			tables = append(tables, channel.Table)
+		}
+	}
+	return tables
+}
+
 func (client *DatabaseClient) GetDatabaseConnection() *sql.DB {
 	return client.sqlite
 }
 
+// Ping checks that the database can still be reached.
+func (client *DatabaseClient) Ping() error {
+	return client.sqlite.Ping()
+}
+
 func (client *DatabaseClient) CloseDatabaseConnection() {
 	if err := client.sqlite.Close(); err != nil {
 		log.Warn().Err(err).Msg("Unable to close database connection")
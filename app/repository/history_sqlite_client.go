@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"github.com/maribowman/roastbeef-swag/app/metrics"
+	"github.com/maribowman/roastbeef-swag/app/model"
+	"github.com/rs/zerolog/log"
+	"time"
+)
+
+// historyLimit is the number of revisions kept per list.
+const historyLimit = 100
+
+type HistorySqliteClient struct {
+	sqlite *sql.DB
+	list   string
+}
+
+func NewHistorySqliteClient(databaseClient model.DatabaseClient, list string) model.HistoryClient {
+	return &HistorySqliteClient{
+		sqlite: databaseClient.GetDatabaseConnection(),
+		list:   list,
+	}
+}
+
+// AddRevision appends a revision after the current one and moves the list to it. Revisions which were undone before
+// are dropped, as they can no longer be redone.
+func (client *HistorySqliteClient) AddRevision(revision model.Revision) (int, error) {
+	defer metrics.ObserveQuery("history", "add_revision")()
+	snapshot, err := json.Marshal(revision.Items)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to serialize revision of %s list", client.list)
+		return -1, err
+	}
+
+	tx, err := client.sqlite.Begin()
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to begin transaction on %s history", client.list)
+		return -1, err
+	}
+	defer tx.Rollback()
+
+	head, err := client.head(tx)
+	if err != nil {
+		return -1, err
+	}
+	if _, err := tx.Exec("delete from history where list=? and id>?;", client.list, head); err != nil {
+		log.Error().Err(err).Msgf("Failed to drop undone revisions of %s list", client.list)
+		return -1, err
+	}
+
+	result, err := tx.Exec("insert into history(list, author, action, items, created_at) values (?, ?, ?, ?, ?);",
+		client.list, revision.Author, revision.Action, string(snapshot), revision.Date.Unix())
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to insert revision into %s history", client.list)
+		return -1, err
+	}
+	id, _ := result.LastInsertId()
+
+	if err := client.setHead(tx, int(id)); err != nil {
+		return -1, err
+	}
+	if _, err := tx.Exec("delete from history where list=? and id not in (select id from history where list=? order by id desc limit ?);",
+		client.list, client.list, historyLimit); err != nil {
+		log.Error().Err(err).Msgf("Failed to truncate %s history", client.list)
+		return -1, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Msgf("Failed to commit revision to %s history", client.list)
+		return -1, err
+	}
+	return int(id), nil
+}
+
+// Undo moves the list one revision back and returns it. It returns nil if there is no older revision.
+func (client *HistorySqliteClient) Undo() (*model.Revision, error) {
+	defer metrics.ObserveQuery("history", "undo")()
+	return client.move("select id, author, action, items, created_at from history where list=? and id<? order by id desc limit 1;")
+}
+
+// Redo moves the list one revision forward and returns it. It returns nil if there is no undone revision.
+func (client *HistorySqliteClient) Redo() (*model.Revision, error) {
+	defer metrics.ObserveQuery("history", "redo")()
+	return client.move("select id, author, action, items, created_at from history where list=? and id>? order by id limit 1;")
+}
+
+// GetRevisions returns the latest revisions, newest first.
+func (client *HistorySqliteClient) GetRevisions(limit int) ([]model.Revision, error) {
+	defer metrics.ObserveQuery("history", "get_revisions")()
+	head, err := client.head(client.sqlite)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := client.sqlite.Query("select id, author, action, items, created_at from history where list=? order by id desc limit ?;", client.list, limit)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to select revisions of %s list", client.list)
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := []model.Revision{}
+	for rows.Next() {
+		revision, err := scanRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		revision.Current = revision.ID == head
+		revisions = append(revisions, revision)
+	}
+	return revisions, rows.Err()
+}
+
+func (client *HistorySqliteClient) move(query string) (*model.Revision, error) {
+	tx, err := client.sqlite.Begin()
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to begin transaction on %s history", client.list)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	head, err := client.head(tx)
+	if err != nil {
+		return nil, err
+	}
+	revision, err := scanRevision(tx.QueryRow(query, client.list, head))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := client.setHead(tx, revision.ID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Msgf("Failed to commit %s history", client.list)
+		return nil, err
+	}
+	revision.Current = true
+	return &revision, nil
+}
+
+type queryRower interface {
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+func (client *HistorySqliteClient) head(queryRower queryRower) (int, error) {
+	var head int
+	err := queryRower.QueryRow("select coalesce((select revision from history_heads where list=?), 0);", client.list).Scan(&head)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to select current revision of %s list", client.list)
+	}
+	return head, err
+}
+
+func (client *HistorySqliteClient) setHead(tx *sql.Tx, revision int) error {
+	_, err := tx.Exec("insert into history_heads(list, revision) values (?, ?) on conflict(list) do update set revision=excluded.revision;", client.list, revision)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to move %s list to revision %d", client.list, revision)
+	}
+	return err
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanRevision(row scanner) (model.Revision, error) {
+	var revision model.Revision
+	var snapshot string
+	var unixDate int64
+	if err := row.Scan(&revision.ID, &revision.Author, &revision.Action, &snapshot, &unixDate); err != nil {
+		if err != sql.ErrNoRows {
+			log.Error().Err(err).Msg("Failed to map row to revision")
+		}
+		return revision, err
+	}
+	if err := json.Unmarshal([]byte(snapshot), &revision.Items); err != nil {
+		log.Error().Err(err).Msgf("Failed to deserialize revision %d", revision.ID)
+		return revision, err
+	}
+	revision.Date = time.Unix(unixDate, 0)
+	return revision, nil
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"github.com/maribowman/roastbeef-swag/app/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestUndoRedo(t *testing.T) {
+	// given
+	databaseClient := newTestDatabaseClient(t)
+	client := NewHistorySqliteClient(databaseClient, "groceries")
+	otherClient := NewHistorySqliteClient(databaseClient, "tk")
+	for _, action := range []string{"first", "second", "third"} {
+		_, err := client.AddRevision(model.Revision{Author: "mari", Action: action, Items: []model.PantryItem{{Item: action}}, Date: time.Now()})
+		assert.NoError(t, err)
+	}
+	_, err := otherClient.AddRevision(model.Revision{Author: "mari", Action: "other", Date: time.Now()})
+	assert.NoError(t, err)
+
+	// when
+	second, err := client.Undo()
+	assert.NoError(t, err)
+	first, err := client.Undo()
+	assert.NoError(t, err)
+	none, err := client.Undo()
+	assert.NoError(t, err)
+	redone, err := client.Redo()
+	assert.NoError(t, err)
+
+	// then
+	assert.Equal(t, "second", second.Action)
+	assert.Equal(t, []model.PantryItem{{Item: "first"}}, first.Items)
+	assert.Nil(t, none)
+	assert.Equal(t, "second", redone.Action)
+
+	// and a new revision drops the undone one
+	_, err = client.AddRevision(model.Revision{Author: "mari", Action: "fourth", Date: time.Now()})
+	assert.NoError(t, err)
+	none, err = client.Redo()
+	assert.NoError(t, err)
+	assert.Nil(t, none)
+
+	revisions, err := client.GetRevisions(10)
+	assert.NoError(t, err)
+	var actions []string
+	for _, revision := range revisions {
+		actions = append(actions, revision.Action)
+	}
+	assert.Equal(t, []string{"fourth", "second", "first"}, actions)
+	assert.True(t, revisions[0].Current)
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"database/sql"
+	"github.com/maribowman/roastbeef-swag/app/metrics"
+	"github.com/maribowman/roastbeef-swag/app/model"
+	"github.com/rs/zerolog/log"
+	"time"
+)
+
+const mealDayFormat = "2006-01-02"
+
+type MealSqliteClient struct {
+	sqlite *sql.DB
+	plan   string
+}
+
+func NewMealSqliteClient(databaseClient model.DatabaseClient, plan string) model.MealClient {
+	return &MealSqliteClient{
+		sqlite: databaseClient.GetDatabaseConnection(),
+		plan:   plan,
+	}
+}
+
+// SaveMeal stores the meal of a day together with its reservations and returns its ID. A meal planned for the same
+// day before is replaced.
+func (client *MealSqliteClient) SaveMeal(meal model.Meal) (int, error) {
+	defer metrics.ObserveQuery("meal", "save_meal")()
+	tx, err := client.sqlite.Begin()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to begin transaction")
+		return -1, err
+	}
+	defer tx.Rollback()
+
+	if err := removeMeal(tx, client.plan, meal.Day); err != nil {
+		log.Error().Err(err).Msgf("Failed to replace meal of %s in %s plan", meal.Day.Format(mealDayFormat), client.plan)
+		return -1, err
+	}
+	result, err := tx.Exec("insert into meals(plan, day, dish, factor, pushed, author, created_at) values (?, ?, ?, ?, ?, ?, ?);",
+		client.plan, meal.Day.Format(mealDayFormat), meal.Dish, meal.Factor, meal.Pushed, meal.Author, time.Now().Unix())
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to insert meal %s into %s plan", meal.Dish, client.plan)
+		return -1, err
+	}
+	id, _ := result.LastInsertId()
+	for _, reservation := range meal.Reserved {
+		if _, err := tx.Exec("insert into meal_reservations(meal_id, list, item_id, item) values (?, ?, ?, ?);",
+			id, reservation.List, reservation.ItemID, reservation.Item); err != nil {
+			log.Error().Err(err).Msgf("Failed to reserve %s for meal %s", reservation.Item, meal.Dish)
+			return -1, err
+		}
+	}
+	return int(id), tx.Commit()
+}
+
+// GetMeals returns the meals planned from the first day until before the second one, ordered by day.
+func (client *MealSqliteClient) GetMeals(from, until time.Time) ([]model.Meal, error) {
+	defer metrics.ObserveQuery("meal", "get_meals")()
+	rows, err := client.sqlite.Query("select id, day, dish, factor, pushed, author from meals where plan=? and day>=? and day<? order by day;",
+		client.plan, from.Format(mealDayFormat), until.Format(mealDayFormat))
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to select %s meals", client.plan)
+		return nil, err
+	}
+	defer rows.Close()
+
+	meals := []model.Meal{}
+	for rows.Next() {
+		var meal model.Meal
+		var day string
+		if err := rows.Scan(&meal.ID, &day, &meal.Dish, &meal.Factor, &meal.Pushed, &meal.Author); err != nil {
+			log.Error().Err(err).Msg("Failed to map row to meal")
+			return nil, err
+		}
+		meal.Day, _ = time.ParseInLocation(mealDayFormat, day, time.Local)
+		meals = append(meals, meal)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for index := range meals {
+		if meals[index].Reserved, err = client.getReservations(meals[index].ID); err != nil {
+			return nil, err
+		}
+	}
+	return meals, nil
+}
+
+func (client *MealSqliteClient) getReservations(mealID int) ([]model.Reservation, error) {
+	rows, err := client.sqlite.Query("select list, item_id, item from meal_reservations where meal_id=? order by rowid;", mealID)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to select reservations of meal %d", mealID)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reservations []model.Reservation
+	for rows.Next() {
+		var reservation model.Reservation
+		if err := rows.Scan(&reservation.List, &reservation.ItemID, &reservation.Item); err != nil {
+			log.Error().Err(err).Msg("Failed to map row to reservation")
+			return nil, err
+		}
+		reservations = append(reservations, reservation)
+	}
+	return reservations, rows.Err()
+}
+
+// RemoveMeal clears the meal of a day. Clearing a day without meal is no error.
+func (client *MealSqliteClient) RemoveMeal(day time.Time) error {
+	defer metrics.ObserveQuery("meal", "remove_meal")()
+	tx, err := client.sqlite.Begin()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to begin transaction")
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := removeMeal(tx, client.plan, day); err != nil {
+		log.Error().Err(err).Msgf("Failed to delete meal of %s from %s plan", day.Format(mealDayFormat), client.plan)
+		return err
+	}
+	return tx.Commit()
+}
+
+func removeMeal(tx *sql.Tx, plan string, day time.Time) error {
+	if _, err := tx.Exec("delete from meal_reservations where meal_id in (select id from meals where plan=? and day=?);", plan, day.Format(mealDayFormat)); err != nil {
+		return err
+	}
+	_, err := tx.Exec("delete from meals where plan=? and day=?;", plan, day.Format(mealDayFormat))
+	return err
+}
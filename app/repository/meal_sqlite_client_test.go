@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"github.com/maribowman/roastbeef-swag/app/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestMeals(t *testing.T) {
+	// given
+	monday := time.Date(2026, 10, 19, 0, 0, 0, 0, time.Local)
+	databaseClient := newTestDatabaseClient(t)
+	client := NewMealSqliteClient(databaseClient, "meals")
+	otherClient := NewMealSqliteClient(databaseClient, "office")
+	lasagne := model.Meal{Day: monday, Dish: "lasagne", Factor: 2, Author: "mari"}
+	salmon := model.Meal{
+		Day:      monday.AddDate(0, 0, 2),
+		Dish:     "salmon",
+		Factor:   1,
+		Reserved: []model.Reservation{{List: "tkGoods", ItemID: 4, Item: "salmon"}, {List: "tkGoods", ItemID: 7, Item: "peas"}},
+		Author:   "mari",
+	}
+
+	// when
+	_, err := client.SaveMeal(lasagne)
+	assert.NoError(t, err)
+	salmon.ID, err = client.SaveMeal(salmon)
+	assert.NoError(t, err)
+	_, err = otherClient.SaveMeal(lasagne)
+	assert.NoError(t, err)
+	lasagne.Dish, lasagne.Pushed = "chili", true
+	lasagne.ID, err = client.SaveMeal(lasagne)
+	assert.NoError(t, err)
+
+	// then
+	meals, err := client.GetMeals(monday, monday.AddDate(0, 0, 7))
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Meal{lasagne, salmon}, meals)
+
+	// and
+	meals, err = client.GetMeals(monday.AddDate(0, 0, 7), monday.AddDate(0, 0, 14))
+	assert.NoError(t, err)
+	assert.Empty(t, meals)
+
+	// and
+	assert.NoError(t, client.RemoveMeal(salmon.Day))
+	assert.NoError(t, client.RemoveMeal(salmon.Day))
+	meals, err = client.GetMeals(monday, monday.AddDate(0, 0, 7))
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Meal{lasagne}, meals)
+	var reservations int
+	assert.NoError(t, databaseClient.GetDatabaseConnection().QueryRow("select count(*) from meal_reservations;").Scan(&reservations))
+	assert.Zero(t, reservations)
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"bytes"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"github.com/rs/zerolog/log"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const (
+	schemaMigrationsDir = "migrations/schema"
+	pantryMigrationsDir = "migrations/pantry"
+	schemaScope         = "schema"
+)
+
+// migrationFiles holds all up-migrations. Files are named `<version>_<name>.sql` and applied in version order.
+// Migrations in `schema` run once per database, migrations in `pantry` are templates that run once per pantry table.
+//
+//go:embed migrations
+var migrationFiles embed.FS
+
+type migration struct {
+	version int
+	name    string
+	query   string
+}
+
+// runMigrations brings the database schema up to date and records every applied migration in `schema_migrations`.
+func runMigrations(sqlite *sql.DB, pantryTables []string) error {
+	if _, err := sqlite.Exec("create table if not exists schema_migrations(scope text not null, version integer not null, name text not null, applied_at int not null, primary key (scope, version));"); err != nil {
+		return fmt.Errorf("could not create schema_migrations table: %w", err)
+	}
+
+	schemaMigrations, err := loadMigrations(schemaMigrationsDir, nil)
+	if err != nil {
+		return err
+	}
+	if err := migrate(sqlite, schemaScope, schemaMigrations); err != nil {
+		return err
+	}
+
+	for _, table := range pantryTables {
+		pantryMigrations, err := loadMigrations(pantryMigrationsDir, map[string]string{"Table": table})
+		if err != nil {
+			return err
+		}
+		if err := migrate(sqlite, table, pantryMigrations); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func loadMigrations(dir string, data any) ([]migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("could not read migrations from %s: %w", dir, err)
+	}
+
+	var migrations []migration
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+		rawVersion, name, _ := strings.Cut(strings.TrimSuffix(entry.Name(), ".sql"), "_")
+		version, err := strconv.Atoi(rawVersion)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in file %s: %w", entry.Name(), err)
+		}
+
+		query, err := template.ParseFS(migrationFiles, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("could not parse migration %s: %w", entry.Name(), err)
+		}
+		rendered := bytes.Buffer{}
+		if err := query.Execute(&rendered, data); err != nil {
+			return nil, fmt.Errorf("could not render migration %s: %w", entry.Name(), err)
+		}
+
+		migrations = append(migrations, migration{version: version, name: name, query: rendered.String()})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].version < migrations[j].version
+	})
+	return migrations, nil
+}
+
+// migrate applies all migrations of a scope which are newer than the latest recorded version. Every migration runs
+// in its own transaction, so a failing migration leaves the database at the last successfully applied version.
+func migrate(sqlite *sql.DB, scope string, migrations []migration) error {
+	var currentVersion int
+	if err := sqlite.QueryRow("select coalesce(max(version), 0) from schema_migrations where scope=?;", scope).Scan(&currentVersion); err != nil {
+		return fmt.Errorf("could not read schema version of %s: %w", scope, err)
+	}
+
+	for _, migration := range migrations {
+		if migration.version <= currentVersion {
+			continue
+		}
+		log.Info().Msgf("Applying migration %04d_%s on %s", migration.version, migration.name, scope)
+
+		tx, err := sqlite.Begin()
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(migration.query); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("migration %04d_%s on %s failed: %w", migration.version, migration.name, scope, err)
+		}
+		if _, err := tx.Exec("insert into schema_migrations(scope, version, name, applied_at) values (?, ?, ?, ?);", scope, migration.version, migration.name, time.Now().Unix()); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("could not record migration %04d_%s on %s: %w", migration.version, migration.name, scope, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRunMigrations(t *testing.T) {
+	// given
+	sqlite := newTestDatabaseClient(t).GetDatabaseConnection()
+
+	// when
+	err := runMigrations(sqlite, testTables)
+
+	// then
+	assert.NoError(t, err)
+	pantryMigrations, _ := loadMigrations(pantryMigrationsDir, map[string]string{"Table": "tk"})
+	for _, table := range testTables {
+		var version, count int
+		assert.NoError(t, sqlite.QueryRow("select max(version), count(*) from schema_migrations where scope=?;", table).Scan(&version, &count))
+		assert.Equal(t, pantryMigrations[len(pantryMigrations)-1].version, version)
+		assert.Equal(t, len(pantryMigrations), count)
+	}
+}
+
+func TestRunMigrationsOnLegacyTable(t *testing.T) {
+	// given
+	sqlite := newTestDatabaseClient(t).GetDatabaseConnection()
+	_, err := sqlite.Exec("drop table schema_migrations; drop table groceries; drop table tk;" +
+		"create table groceries(id integer primary key autoincrement, number integer not null unique, item text not null, amount int not null, date int not null);" +
+		"insert into groceries(number, item, amount, date) values (1, 'eggs', 4, 1703631600);")
+	assert.NoError(t, err)
+
+	// when
+	err = runMigrations(sqlite, testTables)
+
+	// then
+	assert.NoError(t, err)
+	items, err := NewPantrySqliteClient(&DatabaseClient{sqlite: sqlite}, "groceries").GetItems()
+	assert.NoError(t, err)
+	assert.Len(t, items, 1)
+	assert.Equal(t, "eggs", items[0].Item)
+}
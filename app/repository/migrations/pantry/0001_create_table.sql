@@ -0,0 +1,9 @@
+-- matches the table layout created by earlier versions, so existing databases are picked up as they are
+create table if not exists {{.Table}}
+(
+    id     integer primary key autoincrement,
+    number integer not null unique,
+    item   text    not null,
+    amount int     not null,
+    date   int     not null
+);
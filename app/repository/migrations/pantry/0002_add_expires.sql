@@ -0,0 +1,2 @@
+-- optional best-before date as unix timestamp, 0 if unknown
+alter table {{.Table}} add column expires int not null default 0;
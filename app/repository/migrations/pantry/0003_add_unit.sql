@@ -0,0 +1,2 @@
+-- unit of the amount, which may be fractional from now on, empty for items which are just counted
+alter table {{.Table}} add column unit text not null default '';
@@ -0,0 +1,2 @@
+-- short remark on an item, e.g. that it was added automatically
+alter table {{.Table}} add column note text not null default '';
@@ -0,0 +1,2 @@
+-- category given explicitly by a tag like #dairy, empty if it is resolved by keywords
+alter table {{.Table}} add column category text not null default '';
@@ -0,0 +1,2 @@
+-- items ticked off while shopping stay on the list until the trip is finished
+alter table {{.Table}} add column checked int not null default 0;
@@ -0,0 +1,2 @@
+-- user who added the item, empty for items added before authors were recorded
+alter table {{.Table}} add column author text not null default '';
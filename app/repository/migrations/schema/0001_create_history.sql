@@ -0,0 +1,19 @@
+-- every revision holds the full list state after a change
+create table if not exists history
+(
+    id         integer primary key autoincrement,
+    list       text not null,
+    author     text not null,
+    action     text not null,
+    items      text not null,
+    created_at int  not null
+);
+
+create index if not exists history_list_id on history (list, id);
+
+-- revision each list is currently at, revisions after it can be redone
+create table if not exists history_heads
+(
+    list     text primary key,
+    revision integer not null
+);
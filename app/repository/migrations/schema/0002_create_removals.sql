@@ -0,0 +1,16 @@
+-- every item leaving a list with the reason, e.g. purchases of finished shopping trips
+create table if not exists removals
+(
+    id         integer primary key autoincrement,
+    list       text not null,
+    item       text not null,
+    amount     real not null,
+    unit       text not null default '',
+    category   text not null default '',
+    author     text not null,
+    reason     text not null,
+    added_at   int  not null,
+    removed_at int  not null
+);
+
+create index if not exists removals_list_removed_at on removals (list, removed_at);
@@ -0,0 +1,13 @@
+-- recurring items which are added to a list when they are due
+create table if not exists schedules
+(
+    id         integer primary key autoincrement,
+    list       text not null,
+    item       text not null,
+    every      text not null,
+    next_run   int  not null,
+    paused     int  not null default 0,
+    author     text not null,
+    created_at int  not null,
+    unique (list, item, every)
+);
@@ -0,0 +1,8 @@
+-- recipes shared by all lists, ingredients are stored one per line as typed, e.g. `500g minced meat`
+create table if not exists recipes
+(
+    name        text primary key,
+    ingredients text not null,
+    author      text not null,
+    created_at  int  not null
+);
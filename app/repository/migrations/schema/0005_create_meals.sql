@@ -0,0 +1,22 @@
+-- dishes planned per day of a meal plan channel
+create table if not exists meals
+(
+    id         integer primary key autoincrement,
+    plan       text not null,
+    day        text not null,
+    dish       text not null,
+    factor     real not null default 1,
+    pushed     int  not null default 0,
+    author     text not null,
+    created_at int  not null,
+    unique (plan, day)
+);
+
+-- items of other lists, e.g. from the freezer, reserved for a meal
+create table if not exists meal_reservations
+(
+    meal_id integer not null,
+    list    text    not null,
+    item_id integer not null,
+    item    text    not null
+);
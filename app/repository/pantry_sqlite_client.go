@@ -3,6 +3,7 @@ package repository
 import (
 	"database/sql"
 	"fmt"
+	"github.com/maribowman/roastbeef-swag/app/metrics"
 	"github.com/maribowman/roastbeef-swag/app/model"
 	"github.com/rs/zerolog/log"
 	"time"
@@ -14,30 +15,22 @@ type PantrySqliteClient struct {
 }
 
 func NewPantrySqliteClient(databaseClient model.DatabaseClient, tableName string) model.PantryClient {
-	client := &PantrySqliteClient{
+	return &PantrySqliteClient{
 		sqlite:    databaseClient.GetDatabaseConnection(),
 		tableName: tableName,
 	}
-	client.init()
-	return client
-}
-
-func (client *PantrySqliteClient) init() {
-	_, err := client.sqlite.Exec(fmt.Sprintf("create table if not exists %s(id integer primary key autoincrement, number integer not null unique, item text not null, amount int not null, date int not null);", client.tableName))
-	if err != nil {
-		log.Fatal().Err(err).Msgf("Could not create database pantry table %s", client.tableName)
-	}
 }
 
 func (client *PantrySqliteClient) AddItem(item model.PantryItem) (int, error) {
-	stmt, err := client.sqlite.Prepare("insert into ? values (?, ?, ?, ?);")
+	defer metrics.ObserveQuery("pantry", "add_item")()
+	stmt, err := client.sqlite.Prepare(fmt.Sprintf("insert into %s(number, item, amount, unit, date, expires, note, category, checked, author) values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?);", client.tableName))
 	if err != nil {
 		log.Error().Err(err).Msgf("Failed to prepare insert statement on table %s", client.tableName)
 		return -1, err
 	}
 	defer stmt.Close()
 
-	result, err := stmt.Exec(client.tableName, item.Number, item.Item, item.Amount, item.Date.Unix())
+	result, err := stmt.Exec(item.Number, item.Item, item.Amount, item.Unit, item.Date.Unix(), toUnix(item.Expires), item.Note, item.Category, item.Checked, item.Author)
 	if err != nil {
 		log.Error().Err(err).Msgf("Failed to insert item [%s] into %s table", item.ToString(), client.tableName)
 		return -1, err
@@ -47,14 +40,15 @@ func (client *PantrySqliteClient) AddItem(item model.PantryItem) (int, error) {
 }
 
 func (client *PantrySqliteClient) UpdateItem(item model.PantryItem) error {
-	stmt, err := client.sqlite.Prepare("update ? set number=?, item=?, amount=? where id=?;")
+	defer metrics.ObserveQuery("pantry", "update_item")()
+	stmt, err := client.sqlite.Prepare(fmt.Sprintf("update %s set number=?, item=?, amount=?, unit=?, date=?, expires=?, note=?, category=?, checked=?, author=? where id=?;", client.tableName))
 	if err != nil {
 		log.Error().Err(err).Msgf("Failed to prepare update statement on table %s", client.tableName)
 		return err
 	}
 	defer stmt.Close()
 
-	if _, err := stmt.Exec(client.tableName, item.Number, item.Item, item.Amount, item.ID); err != nil {
+	if _, err := stmt.Exec(item.Number, item.Item, item.Amount, item.Unit, item.Date.Unix(), toUnix(item.Expires), item.Note, item.Category, item.Checked, item.Author, item.ID); err != nil {
 		log.Error().Err(err).Msgf("Failed to update item [%s] in %s table", item.ToString(), client.tableName)
 		return err
 	}
@@ -62,44 +56,149 @@ func (client *PantrySqliteClient) UpdateItem(item model.PantryItem) error {
 }
 
 func (client *PantrySqliteClient) RemoveItem(id int) error {
-	stmt, err := client.sqlite.Prepare("delete from ? where id=?;")
+	defer metrics.ObserveQuery("pantry", "remove_item")()
+	stmt, err := client.sqlite.Prepare(fmt.Sprintf("delete from %s where id=?;", client.tableName))
 	if err != nil {
 		log.Error().Err(err).Msgf("Failed to prepare delete statement on table %s", client.tableName)
 		return err
 	}
 	defer stmt.Close()
 
-	if _, err = stmt.Exec(client.tableName, id); err != nil {
+	if _, err = stmt.Exec(id); err != nil {
 		log.Error().Err(err).Msgf("Failed to delete item [id: `%d`] in %s table", id, client.tableName)
+		return err
 	}
 	return nil
 }
 
 func (client *PantrySqliteClient) GetItems() ([]model.PantryItem, error) {
-	stmt, err := client.sqlite.Prepare("select * from ?;")
+	defer metrics.ObserveQuery("pantry", "get_items")()
+	stmt, err := client.sqlite.Prepare(fmt.Sprintf("select id, number, item, amount, unit, date, expires, note, category, checked, author from %s order by number;", client.tableName))
 	if err != nil {
 		log.Error().Err(err).Msgf("Failed to prepare select all statement on table %s", client.tableName)
 		return []model.PantryItem{}, err
 	}
 	defer stmt.Close()
 
-	rows, err := stmt.Query(client.tableName)
+	rows, err := stmt.Query()
 	if err != nil {
 		log.Error().Err(err).Msgf("Failed to select all items from %s table", client.tableName)
 		return []model.PantryItem{}, err
 	}
 
-	var items []model.PantryItem
+	items := []model.PantryItem{}
 	defer rows.Close()
 	for rows.Next() {
 		var item model.PantryItem
-		var unixDate int64
-		err := rows.Scan(&item.ID, &item.Number, &item.Item, &item.Amount, &unixDate)
+		var unixDate, unixExpires int64
+		err := rows.Scan(&item.ID, &item.Number, &item.Item, &item.Amount, &item.Unit, &unixDate, &unixExpires, &item.Note, &item.Category, &item.Checked, &item.Author)
 		if err != nil {
 			log.Error().Err(err).Msg("Failed to map row to pantry item")
+			return []model.PantryItem{}, err
 		}
 		item.Date = time.Unix(unixDate, 0)
+		item.Expires = fromUnix(unixExpires)
 		items = append(items, item)
 	}
-	return items, nil
+	return items, rows.Err()
+}
+
+// ReplaceItems atomically overwrites the table with the given list. Items keep their ID if they already have one,
+// new items (ID 0) get one assigned. Numbers are normalized to the position in the list.
+func (client *PantrySqliteClient) ReplaceItems(items []model.PantryItem) ([]model.PantryItem, error) {
+	defer metrics.ObserveQuery("pantry", "replace_items")()
+	tx, err := client.sqlite.Begin()
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to begin transaction on table %s", client.tableName)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	stored, err := replaceItems(tx, client.tableName, items)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Msgf("Failed to commit items to %s table", client.tableName)
+		return nil, err
+	}
+	return stored, nil
+}
+
+// MoveItems overwrites this table with items and the table of the target with targetItems in a single transaction,
+// so items moved between lists are never lost or duplicated. Both tables must be in the same database.
+func (client *PantrySqliteClient) MoveItems(items []model.PantryItem, target model.PantryClient, targetItems []model.PantryItem) ([]model.PantryItem, []model.PantryItem, error) {
+	defer metrics.ObserveQuery("pantry", "move_items")()
+	targetClient, ok := target.(*PantrySqliteClient)
+	if !ok || targetClient.sqlite != client.sqlite {
+		return nil, nil, fmt.Errorf("cannot move items from %s table to another database", client.tableName)
+	}
+
+	tx, err := client.sqlite.Begin()
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to begin transaction on tables %s and %s", client.tableName, targetClient.tableName)
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	stored, err := replaceItems(tx, client.tableName, items)
+	if err != nil {
+		return nil, nil, err
+	}
+	storedTarget, err := replaceItems(tx, targetClient.tableName, targetItems)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Msgf("Failed to commit items to tables %s and %s", client.tableName, targetClient.tableName)
+		return nil, nil, err
+	}
+	return stored, storedTarget, nil
+}
+
+func replaceItems(tx *sql.Tx, tableName string, items []model.PantryItem) ([]model.PantryItem, error) {
+	if _, err := tx.Exec(fmt.Sprintf("delete from %s;", tableName)); err != nil {
+		log.Error().Err(err).Msgf("Failed to clear %s table", tableName)
+		return nil, err
+	}
+
+	stmt, err := tx.Prepare(fmt.Sprintf("insert into %s(id, number, item, amount, unit, date, expires, note, category, checked, author) values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);", tableName))
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to prepare insert statement on table %s", tableName)
+		return nil, err
+	}
+	defer stmt.Close()
+
+	stored := make([]model.PantryItem, 0, len(items))
+	for index, item := range items {
+		var id any
+		if item.ID > 0 {
+			id = item.ID
+		}
+		item.Number = index + 1
+		result, err := stmt.Exec(id, item.Number, item.Item, item.Amount, item.Unit, item.Date.Unix(), toUnix(item.Expires), item.Note, item.Category, item.Checked, item.Author)
+		if err != nil {
+			log.Error().Err(err).Msgf("Failed to insert item [%s] into %s table", item.ToString(), tableName)
+			return nil, err
+		}
+		lastID, _ := result.LastInsertId()
+		item.ID = int(lastID)
+		stored = append(stored, item)
+	}
+	return stored, nil
+}
+
+// toUnix stores optional dates, the zero time is stored as 0.
+func toUnix(date time.Time) int64 {
+	if date.IsZero() {
+		return 0
+	}
+	return date.Unix()
+}
+
+func fromUnix(unix int64) time.Time {
+	if unix == 0 {
+		return time.Time{}
+	}
+	return time.Unix(unix, 0)
 }
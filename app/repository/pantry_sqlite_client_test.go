@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"database/sql"
+	"github.com/maribowman/roastbeef-swag/app/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+var testTables = []string{"groceries", "tk"}
+
+func newTestDatabaseClient(t *testing.T) model.DatabaseClient {
+	sqlite, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlite.SetMaxOpenConns(1) // every connection opens its own in-memory database
+	t.Cleanup(func() { _ = sqlite.Close() })
+	if err := runMigrations(sqlite, testTables); err != nil {
+		t.Fatal(err)
+	}
+	return &DatabaseClient{sqlite: sqlite}
+}
+
+func TestReplaceItems(t *testing.T) {
+	// given
+	date := time.Date(2023, 12, 27, 0, 0, 0, 0, time.Local)
+	client := NewPantrySqliteClient(newTestDatabaseClient(t), "groceries")
+	initial, err := client.ReplaceItems([]model.PantryItem{
+		{Item: "eggs", Amount: 4, Date: date, Note: "auto-added", Checked: true, Author: "mari"},
+		{Item: "coffee", Amount: 1, Date: date},
+		{Item: "bacon", Amount: 3, Date: date, Expires: date.AddDate(1, 0, 0), Category: "meat"},
+	})
+	assert.NoError(t, err)
+
+	// when
+	updated, err := client.ReplaceItems([]model.PantryItem{initial[2], initial[0], {Item: "milk", Amount: 1.5, Unit: "l", Date: date}})
+
+	// then
+	assert.NoError(t, err)
+	assert.EqualValues(t, []model.PantryItem{
+		{ID: initial[2].ID, Number: 1, Item: "bacon", Amount: 3, Date: date, Expires: date.AddDate(1, 0, 0), Category: "meat"},
+		{ID: initial[0].ID, Number: 2, Item: "eggs", Amount: 4, Date: date, Note: "auto-added", Checked: true, Author: "mari"},
+		{ID: initial[2].ID + 1, Number: 3, Item: "milk", Amount: 1.5, Unit: "l", Date: date},
+	}, updated)
+
+	// and
+	stored, err := client.GetItems()
+	assert.NoError(t, err)
+	assert.EqualValues(t, updated, stored)
+}
+
+func TestMoveItems(t *testing.T) {
+	// given
+	date := time.Date(2023, 12, 27, 0, 0, 0, 0, time.Local)
+	databaseClient := newTestDatabaseClient(t)
+	groceries := NewPantrySqliteClient(databaseClient, "groceries")
+	tk := NewPantrySqliteClient(databaseClient, "tk")
+	items, err := groceries.ReplaceItems([]model.PantryItem{
+		{Item: "eggs", Amount: 4, Date: date},
+		{Item: "salmon", Amount: 2, Date: date},
+	})
+	assert.NoError(t, err)
+
+	// when
+	moved := items[1]
+	moved.ID = 0
+	remaining, targetItems, err := groceries.MoveItems(items[:1], tk, []model.PantryItem{moved})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, items[:1], remaining)
+	assert.Len(t, targetItems, 1)
+	storedGroceries, _ := groceries.GetItems()
+	storedTk, _ := tk.GetItems()
+	assert.EqualValues(t, remaining, storedGroceries)
+	assert.EqualValues(t, targetItems, storedTk)
+}
+
+func TestMoveItemsIsAtomic(t *testing.T) {
+	// given
+	date := time.Date(2023, 12, 27, 0, 0, 0, 0, time.Local)
+	databaseClient := newTestDatabaseClient(t)
+	groceries := NewPantrySqliteClient(databaseClient, "groceries")
+	items, err := groceries.ReplaceItems([]model.PantryItem{{Item: "salmon", Amount: 2, Date: date}})
+	assert.NoError(t, err)
+
+	// when
+	_, _, err = groceries.MoveItems(nil, NewPantrySqliteClient(databaseClient, "missing"), items)
+
+	// then
+	assert.Error(t, err)
+	stored, _ := groceries.GetItems()
+	assert.EqualValues(t, items, stored)
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/maribowman/roastbeef-swag/app/metrics"
+	"github.com/maribowman/roastbeef-swag/app/model"
+	"github.com/rs/zerolog/log"
+	"strings"
+	"time"
+)
+
+type RecipeSqliteClient struct {
+	sqlite *sql.DB
+}
+
+func NewRecipeSqliteClient(databaseClient model.DatabaseClient) model.RecipeClient {
+	return &RecipeSqliteClient{
+		sqlite: databaseClient.GetDatabaseConnection(),
+	}
+}
+
+// SaveRecipe stores a recipe, an existing recipe with the same name is replaced.
+func (client *RecipeSqliteClient) SaveRecipe(recipe model.Recipe) error {
+	defer metrics.ObserveQuery("recipe", "save_recipe")()
+	_, err := client.sqlite.Exec("insert into recipes(name, ingredients, author, created_at) values (?, ?, ?, ?) on conflict(name) do update set ingredients=excluded.ingredients, author=excluded.author, created_at=excluded.created_at;",
+		recipe.Name, strings.Join(recipe.Ingredients, "\n"), recipe.Author, time.Now().Unix())
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to save recipe %s", recipe.Name)
+	}
+	return err
+}
+
+// GetRecipes returns all recipes ordered by name.
+func (client *RecipeSqliteClient) GetRecipes() ([]model.Recipe, error) {
+	defer metrics.ObserveQuery("recipe", "get_recipes")()
+	rows, err := client.sqlite.Query("select name, ingredients, author from recipes order by name;")
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to select recipes")
+		return nil, err
+	}
+	defer rows.Close()
+
+	recipes := []model.Recipe{}
+	for rows.Next() {
+		var recipe model.Recipe
+		var ingredients string
+		if err := rows.Scan(&recipe.Name, &ingredients, &recipe.Author); err != nil {
+			log.Error().Err(err).Msg("Failed to map row to recipe")
+			return nil, err
+		}
+		recipe.Ingredients = strings.Split(ingredients, "\n")
+		recipes = append(recipes, recipe)
+	}
+	return recipes, rows.Err()
+}
+
+func (client *RecipeSqliteClient) RemoveRecipe(name string) error {
+	defer metrics.ObserveQuery("recipe", "remove_recipe")()
+	result, err := client.sqlite.Exec("delete from recipes where name=?;", name)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to delete recipe %s", name)
+		return err
+	}
+	if removed, _ := result.RowsAffected(); removed == 0 {
+		return fmt.Errorf("there is no recipe `%s`", name)
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"github.com/maribowman/roastbeef-swag/app/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRecipes(t *testing.T) {
+	// given
+	client := NewRecipeSqliteClient(newTestDatabaseClient(t))
+	assert.NoError(t, client.SaveRecipe(model.Recipe{Name: "lasagne", Ingredients: []string{"500g minced meat"}, Author: "mari"}))
+	assert.NoError(t, client.SaveRecipe(model.Recipe{Name: "pancakes", Ingredients: []string{"3 eggs", "250 g flour", "0,5 l milk"}, Author: "mari"}))
+
+	// when
+	err := client.SaveRecipe(model.Recipe{Name: "lasagne", Ingredients: []string{"500g minced meat", "2 onions"}, Author: "tom"})
+
+	// then
+	assert.NoError(t, err)
+	recipes, err := client.GetRecipes()
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Recipe{
+		{Name: "lasagne", Ingredients: []string{"500g minced meat", "2 onions"}, Author: "tom"},
+		{Name: "pancakes", Ingredients: []string{"3 eggs", "250 g flour", "0,5 l milk"}, Author: "mari"},
+	}, recipes)
+
+	// and
+	assert.NoError(t, client.RemoveRecipe("pancakes"))
+	assert.Error(t, client.RemoveRecipe("pancakes"))
+	recipes, _ = client.GetRecipes()
+	assert.Len(t, recipes, 1)
+}
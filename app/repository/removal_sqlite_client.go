@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"database/sql"
+	"github.com/maribowman/roastbeef-swag/app/metrics"
+	"github.com/maribowman/roastbeef-swag/app/model"
+	"github.com/rs/zerolog/log"
+	"time"
+)
+
+type RemovalSqliteClient struct {
+	sqlite *sql.DB
+	list   string
+}
+
+func NewRemovalSqliteClient(databaseClient model.DatabaseClient, list string) model.RemovalClient {
+	return &RemovalSqliteClient{
+		sqlite: databaseClient.GetDatabaseConnection(),
+		list:   list,
+	}
+}
+
+// AddRemovals records all items removed by a single change in one transaction.
+func (client *RemovalSqliteClient) AddRemovals(removals []model.Removal) error {
+	defer metrics.ObserveQuery("removal", "add_removals")()
+	tx, err := client.sqlite.Begin()
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to begin transaction on %s removals", client.list)
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare("insert into removals(list, item, amount, unit, category, author, reason, added_at, removed_at) values (?, ?, ?, ?, ?, ?, ?, ?, ?);")
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to prepare insert statement on removals")
+		return err
+	}
+	defer stmt.Close()
+
+	for _, removal := range removals {
+		if _, err := stmt.Exec(client.list, removal.Item, removal.Amount, removal.Unit, removal.Category, removal.Author, removal.Reason,
+			removal.Added.Unix(), removal.Removed.Unix()); err != nil {
+			log.Error().Err(err).Msgf("Failed to insert removal of %s into %s removals", removal.Item, client.list)
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Msgf("Failed to commit %s removals", client.list)
+		return err
+	}
+	return nil
+}
+
+// GetRemovals returns all removals since the given time, oldest first.
+func (client *RemovalSqliteClient) GetRemovals(since time.Time) ([]model.Removal, error) {
+	defer metrics.ObserveQuery("removal", "get_removals")()
+	rows, err := client.sqlite.Query("select id, list, item, amount, unit, category, author, reason, added_at, removed_at from removals where list=? and removed_at>=? order by removed_at, id;",
+		client.list, since.Unix())
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to select %s removals", client.list)
+		return nil, err
+	}
+	defer rows.Close()
+
+	removals := []model.Removal{}
+	for rows.Next() {
+		var removal model.Removal
+		var unixAdded, unixRemoved int64
+		if err := rows.Scan(&removal.ID, &removal.List, &removal.Item, &removal.Amount, &removal.Unit, &removal.Category, &removal.Author,
+			&removal.Reason, &unixAdded, &unixRemoved); err != nil {
+			log.Error().Err(err).Msg("Failed to map row to removal")
+			return nil, err
+		}
+		removal.Added = time.Unix(unixAdded, 0)
+		removal.Removed = time.Unix(unixRemoved, 0)
+		removals = append(removals, removal)
+	}
+	return removals, rows.Err()
+}
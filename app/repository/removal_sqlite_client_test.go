@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"github.com/maribowman/roastbeef-swag/app/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestRemovals(t *testing.T) {
+	// given
+	date := time.Date(2023, 12, 27, 0, 0, 0, 0, time.Local)
+	databaseClient := newTestDatabaseClient(t)
+	client := NewRemovalSqliteClient(databaseClient, "groceries")
+	assert.NoError(t, NewRemovalSqliteClient(databaseClient, "tk").AddRemovals([]model.Removal{
+		{Item: "pizza", Amount: 1, Author: "mari", Reason: model.RemovedReason, Added: date, Removed: date},
+	}))
+
+	// when
+	err := client.AddRemovals([]model.Removal{
+		{Item: "eggs", Amount: 6, Author: "mari", Reason: model.RemovedReason, Added: date.AddDate(0, 0, -9), Removed: date.AddDate(0, 0, -7)},
+		{Item: "milk", Amount: 1.5, Unit: "l", Category: "dairy", Author: "mari", Reason: model.PurchasedReason, Added: date.AddDate(0, 0, -1), Removed: date},
+	})
+
+	// then
+	assert.NoError(t, err)
+	removals, err := client.GetRemovals(date.AddDate(0, 0, -1))
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Removal{{
+		ID: removals[0].ID, List: "groceries", Item: "milk", Amount: 1.5, Unit: "l", Category: "dairy", Author: "mari",
+		Reason: model.PurchasedReason, Added: date.AddDate(0, 0, -1), Removed: date,
+	}}, removals)
+
+	// and
+	all, err := client.GetRemovals(time.Time{})
+	assert.NoError(t, err)
+	assert.Len(t, all, 2)
+	assert.Equal(t, 2.0, all[0].Days())
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/maribowman/roastbeef-swag/app/metrics"
+	"github.com/maribowman/roastbeef-swag/app/model"
+	"github.com/rs/zerolog/log"
+	"time"
+)
+
+type ScheduleSqliteClient struct {
+	sqlite *sql.DB
+	list   string
+}
+
+func NewScheduleSqliteClient(databaseClient model.DatabaseClient, list string) model.ScheduleClient {
+	return &ScheduleSqliteClient{
+		sqlite: databaseClient.GetDatabaseConnection(),
+		list:   list,
+	}
+}
+
+// AddSchedule stores a new schedule and returns its ID. It returns 0 if the same item is already scheduled with the
+// same recurrence.
+func (client *ScheduleSqliteClient) AddSchedule(schedule model.Schedule) (int, error) {
+	defer metrics.ObserveQuery("schedule", "add_schedule")()
+	result, err := client.sqlite.Exec("insert into schedules(list, item, every, next_run, paused, author, created_at) values (?, ?, ?, ?, ?, ?, ?) on conflict(list, item, every) do nothing;",
+		client.list, schedule.Item, schedule.Every, schedule.Next.Unix(), schedule.Paused, schedule.Author, schedule.Created.Unix())
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to insert schedule of %s into %s schedules", schedule.Item, client.list)
+		return -1, err
+	}
+	if inserted, _ := result.RowsAffected(); inserted == 0 {
+		return 0, nil
+	}
+	id, _ := result.LastInsertId()
+	return int(id), nil
+}
+
+// GetSchedules returns all schedules of the list in the order they were added.
+func (client *ScheduleSqliteClient) GetSchedules() ([]model.Schedule, error) {
+	defer metrics.ObserveQuery("schedule", "get_schedules")()
+	rows, err := client.sqlite.Query("select id, item, every, next_run, paused, author, created_at from schedules where list=? order by id;", client.list)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to select %s schedules", client.list)
+		return nil, err
+	}
+	defer rows.Close()
+
+	schedules := []model.Schedule{}
+	for rows.Next() {
+		var schedule model.Schedule
+		var unixNext, unixCreated int64
+		if err := rows.Scan(&schedule.ID, &schedule.Item, &schedule.Every, &unixNext, &schedule.Paused, &schedule.Author, &unixCreated); err != nil {
+			log.Error().Err(err).Msg("Failed to map row to schedule")
+			return nil, err
+		}
+		schedule.Next = time.Unix(unixNext, 0)
+		schedule.Created = time.Unix(unixCreated, 0)
+		schedules = append(schedules, schedule)
+	}
+	return schedules, rows.Err()
+}
+
+// UpdateSchedule stores the next run and the paused state of a schedule.
+func (client *ScheduleSqliteClient) UpdateSchedule(schedule model.Schedule) error {
+	defer metrics.ObserveQuery("schedule", "update_schedule")()
+	result, err := client.sqlite.Exec("update schedules set next_run=?, paused=? where list=? and id=?;", schedule.Next.Unix(), schedule.Paused, client.list, schedule.ID)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to update schedule %d of %s list", schedule.ID, client.list)
+		return err
+	}
+	if updated, _ := result.RowsAffected(); updated == 0 {
+		return fmt.Errorf("there is no schedule %d", schedule.ID)
+	}
+	return nil
+}
+
+func (client *ScheduleSqliteClient) RemoveSchedule(id int) error {
+	defer metrics.ObserveQuery("schedule", "remove_schedule")()
+	result, err := client.sqlite.Exec("delete from schedules where list=? and id=?;", client.list, id)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to delete schedule %d of %s list", id, client.list)
+		return err
+	}
+	if removed, _ := result.RowsAffected(); removed == 0 {
+		return fmt.Errorf("there is no schedule %d", id)
+	}
+	return nil
+}
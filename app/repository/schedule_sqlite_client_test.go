@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"github.com/maribowman/roastbeef-swag/app/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestSchedules(t *testing.T) {
+	// given
+	date := time.Date(2023, 12, 27, 8, 0, 0, 0, time.Local)
+	databaseClient := newTestDatabaseClient(t)
+	client := NewScheduleSqliteClient(databaseClient, "groceries")
+	otherClient := NewScheduleSqliteClient(databaseClient, "tk")
+	milk := model.Schedule{Item: "milk 2", Every: "monday", Next: date, Author: "mari", Created: date}
+
+	// when
+	id, err := client.AddSchedule(milk)
+	assert.NoError(t, err)
+	duplicateID, err := client.AddSchedule(milk)
+	assert.NoError(t, err)
+	otherID, err := otherClient.AddSchedule(milk)
+	assert.NoError(t, err)
+
+	// then
+	assert.Positive(t, id)
+	assert.Zero(t, duplicateID)
+	assert.Positive(t, otherID)
+
+	// and
+	milk.ID = id
+	milk.Next = date.AddDate(0, 0, 7)
+	milk.Paused = true
+	assert.NoError(t, client.UpdateSchedule(milk))
+	schedules, err := client.GetSchedules()
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Schedule{milk}, schedules)
+
+	// and schedules of other lists are out of reach
+	assert.Error(t, client.RemoveSchedule(otherID))
+	assert.NoError(t, client.RemoveSchedule(id))
+	schedules, err = client.GetSchedules()
+	assert.NoError(t, err)
+	assert.Empty(t, schedules)
+}
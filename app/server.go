@@ -12,18 +12,23 @@ import (
 )
 
 func InitServer(databaseClient model.DatabaseClient) (*http.Server, model.DiscordBot, error) {
+	bot := service.NewDiscordBot(databaseClient)
 	return &http.Server{
 		Addr:    fmt.Sprintf(":%d", config.Config.Server.Port),
-		Handler: injectRouter(),
-	}, service.NewDiscordBot(databaseClient), nil
+		Handler: injectRouter(databaseClient, bot),
+	}, bot, nil
 }
 
-func injectRouter() *gin.Engine {
+func injectRouter(databaseClient model.DatabaseClient, bot model.DiscordBot) *gin.Engine {
 	gin.SetMode(config.Config.Server.Mode)
 	router := gin.New()
 	controller.NewController(&controller.Wiring{
 		Router:            router,
 		PrometheusHandler: promhttp.Handler(),
+		ListHandlers:      bot.GetListHandlers(),
+		DatabaseClient:    databaseClient,
+		Bot:               bot,
+		ApiToken:          config.Config.Server.ApiToken,
 	})
 	return router
 }
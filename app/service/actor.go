@@ -0,0 +1,67 @@
+package service
+
+import (
+	"cmp"
+	"slices"
+	"sync/atomic"
+)
+
+// actorSequence numbers all actors, as several channels may share a table.
+var actorSequence atomic.Uint64
+
+// actor serializes the work on a channel. Callers queue up in the order they arrive and hold the actor exclusively
+// until they release it, so the tasks of a channel never interleave, while different channels work in parallel.
+type actor struct {
+	order uint64 // unique, orders actors which are held together, see holdAll
+	queue chan func()
+}
+
+func newActor() *actor {
+	actor := &actor{order: actorSequence.Add(1), queue: make(chan func())}
+	go actor.run()
+	return actor
+}
+
+func (actor *actor) run() {
+	for task := range actor.queue {
+		task()
+	}
+}
+
+// hold waits until it is the turn of the caller and blocks the actor until the returned release is called.
+func (actor *actor) hold() func() {
+	held, released := make(chan struct{}), make(chan struct{})
+	actor.queue <- func() {
+		close(held)
+		<-released
+	}
+	<-held
+	return func() { close(released) }
+}
+
+// do runs the task while holding the actor. A task must never wait for its own actor.
+func (actor *actor) do(task func()) {
+	release := actor.hold()
+	defer release()
+	task()
+}
+
+// holdAll holds several actors at once. Actors are always held in the order of their creation, so two callers
+// holding the same actors never wait for each other crosswise.
+func holdAll(actors ...*actor) func() {
+	sorted := slices.Clone(actors)
+	slices.SortFunc(sorted, func(a, b *actor) int {
+		return cmp.Compare(a.order, b.order)
+	})
+	var releases []func()
+	for index, actor := range sorted {
+		if !slices.Contains(sorted[:index], actor) {
+			releases = append(releases, actor.hold())
+		}
+	}
+	return func() {
+		for index := len(releases) - 1; index >= 0; index-- {
+			releases[index]()
+		}
+	}
+}
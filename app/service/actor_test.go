@@ -0,0 +1,94 @@
+package service
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestActorRunsTasksOneAfterAnother(t *testing.T) {
+	// given
+	actor := newActor()
+	counter, running, overlaps := 0, 0, 0
+	var wait sync.WaitGroup
+
+	// when
+	for range 100 {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			actor.do(func() {
+				running++
+				if running > 1 {
+					overlaps++
+				}
+				counter++
+				running--
+			})
+		}()
+	}
+	wait.Wait()
+
+	// then
+	assert.Equal(t, 100, counter)
+	assert.Zero(t, overlaps)
+}
+
+func TestActorKeepsOrderOfCallers(t *testing.T) {
+	// given
+	actor := newActor()
+	release := actor.hold()
+	var order []int
+	var wait sync.WaitGroup
+
+	// when
+	for index := range 5 {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			actor.do(func() { order = append(order, index) })
+		}()
+		time.Sleep(10 * time.Millisecond) // let the caller queue up
+	}
+	release()
+	wait.Wait()
+
+	// then
+	assert.Equal(t, []int{0, 1, 2, 3, 4}, order)
+}
+
+func TestHoldAll(t *testing.T) {
+	// given
+	groceries, tk := newActor(), newActor()
+	var wait sync.WaitGroup
+	moves := 0
+
+	// when
+	for index := range 100 {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			source, target := groceries, tk
+			if index%2 == 0 {
+				source, target = tk, groceries
+			}
+			release := holdAll(source, target, source)
+			moves++
+			release()
+		}()
+	}
+	done := make(chan struct{})
+	go func() {
+		wait.Wait()
+		close(done)
+	}()
+
+	// then
+	select {
+	case <-done:
+		assert.Equal(t, 100, moves)
+	case <-time.After(5 * time.Second):
+		t.Fatal("actors held crosswise")
+	}
+}
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"github.com/maribowman/roastbeef-swag/app/config"
+	"github.com/maribowman/roastbeef-swag/app/model"
+)
+
+// aislePantryClient keeps a list sorted by the aisle order of its channel, so that item numbers follow the order of
+// the rendered table.
+type aislePantryClient struct {
+	model.PantryClient
+	aisles []string
+}
+
+// newAislePantryClient wraps the pantry client of a channel. Lists without aisles keep the order of their items.
+func newAislePantryClient(pantryClient model.PantryClient, aisles []string) model.PantryClient {
+	return &aislePantryClient{PantryClient: pantryClient, aisles: aisles}
+}
+
+func (client *aislePantryClient) ReplaceItems(items []model.PantryItem) ([]model.PantryItem, error) {
+	return client.PantryClient.ReplaceItems(model.SortByAisle(items, client.aisles, config.Config.Discord.Categories))
+}
+
+func (client *aislePantryClient) MoveItems(items []model.PantryItem, target model.PantryClient, targetItems []model.PantryItem) ([]model.PantryItem, []model.PantryItem, error) {
+	if targetClient, ok := target.(*aislePantryClient); ok {
+		targetItems = model.SortByAisle(targetItems, targetClient.aisles, config.Config.Discord.Categories)
+		target = targetClient.PantryClient
+	}
+	return client.PantryClient.MoveItems(model.SortByAisle(items, client.aisles, config.Config.Discord.Categories), target, targetItems)
+}
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"fmt"
+	"github.com/bwmarrin/discordgo"
+	"github.com/maribowman/roastbeef-swag/app/model"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	fakeBotID = "bot"
+
+	// bulkDeleteMaxAge is the age from which Discord rejects deleting messages in bulk.
+	bulkDeleteMaxAge = 14 * 24 * time.Hour
+)
+
+// fakeChatSession keeps the messages of all channels in memory, so that handlers can be tested end to end without a
+// live bot. Messages are stored oldest first and copied on every access, as handlers run concurrently.
+type fakeChatSession struct {
+	mutex     sync.Mutex
+	messages  map[string][]*discordgo.Message
+	responses []*discordgo.InteractionResponse
+	followups []*discordgo.WebhookParams
+	nextID    int
+}
+
+func newFakeChatSession() *fakeChatSession {
+	return &fakeChatSession{messages: map[string][]*discordgo.Message{}}
+}
+
+func (session *fakeChatSession) ChannelMessages(channelID string, limit int, _, _, _ string, _ ...discordgo.RequestOption) ([]*discordgo.Message, error) {
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
+	var messages []*discordgo.Message
+	for _, message := range slices.Backward(session.messages[channelID]) { // newest first like Discord
+		if len(messages) == limit {
+			break
+		}
+		copied := *message
+		messages = append(messages, &copied)
+	}
+	return messages, nil
+}
+
+func (session *fakeChatSession) ChannelMessageSendComplex(channelID string, data *discordgo.MessageSend, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
+	message := session.add(channelID, &discordgo.Message{
+		Author:     &discordgo.User{ID: fakeBotID, Username: fakeBotID},
+		Content:    data.Content,
+		Components: data.Components,
+	})
+	copied := *message
+	return &copied, nil
+}
+
+func (session *fakeChatSession) ChannelMessageEditComplex(data *discordgo.MessageEdit, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
+	for _, message := range session.messages[data.Channel] {
+		if message.ID != data.ID {
+			continue
+		}
+		if data.Content != nil {
+			message.Content = *data.Content
+		}
+		if data.Components != nil {
+			message.Components = *data.Components
+		}
+		copied := *message
+		return &copied, nil
+	}
+	return nil, fmt.Errorf("unknown message %s", data.ID)
+}
+
+func (session *fakeChatSession) ChannelMessageDelete(channelID, messageID string, _ ...discordgo.RequestOption) error {
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
+	session.messages[channelID] = slices.DeleteFunc(session.messages[channelID], func(message *discordgo.Message) bool {
+		return message.ID == messageID
+	})
+	return nil
+}
+
+// ChannelMessagesBulkDelete fails like Discord if any of several messages is older than two weeks, a single message
+// is deleted like by ChannelMessageDelete.
+func (session *fakeChatSession) ChannelMessagesBulkDelete(channelID string, messageIDs []string, _ ...discordgo.RequestOption) error {
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
+	if len(messageIDs) > 1 && slices.ContainsFunc(session.messages[channelID], func(message *discordgo.Message) bool {
+		return slices.Contains(messageIDs, message.ID) && time.Since(message.Timestamp) > bulkDeleteMaxAge
+	}) {
+		return fmt.Errorf("cannot bulk delete messages older than two weeks in channel %s", channelID)
+	}
+	session.messages[channelID] = slices.DeleteFunc(session.messages[channelID], func(message *discordgo.Message) bool {
+		return slices.Contains(messageIDs, message.ID)
+	})
+	return nil
+}
+
+func (session *fakeChatSession) InteractionRespond(_ *discordgo.Interaction, response *discordgo.InteractionResponse, _ ...discordgo.RequestOption) error {
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
+	session.responses = append(session.responses, response)
+	return nil
+}
+
+func (session *fakeChatSession) FollowupMessageCreate(_ *discordgo.Interaction, _ bool, data *discordgo.WebhookParams, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
+	session.followups = append(session.followups, data)
+	return &discordgo.Message{Content: data.Content}, nil
+}
+
+func (session *fakeChatSession) add(channelID string, message *discordgo.Message) *discordgo.Message {
+	session.nextID++
+	message.ID = strconv.Itoa(session.nextID)
+	message.ChannelID = channelID
+	message.Timestamp = time.Now().Add(time.Duration(session.nextID) * time.Millisecond) // strictly ascending
+	session.messages[channelID] = append(session.messages[channelID], message)
+	return message
+}
+
+// age makes all messages of a channel older by the given duration.
+func (session *fakeChatSession) age(channelID string, duration time.Duration) {
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
+	for _, message := range session.messages[channelID] {
+		message.Timestamp = message.Timestamp.Add(-duration)
+	}
+}
+
+// post adds a user message to a channel and returns the event Discord would send for it.
+func (session *fakeChatSession) post(channelID, author, content string) *discordgo.MessageCreate {
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
+	message := session.add(channelID, &discordgo.Message{Author: &discordgo.User{ID: author, Username: author}, Content: content})
+	copied := *message
+	return &discordgo.MessageCreate{Message: &copied}
+}
+
+// channelMessages returns the messages of a channel, oldest first.
+func (session *fakeChatSession) channelMessages(channelID string) []discordgo.Message {
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
+	var messages []discordgo.Message
+	for _, message := range session.messages[channelID] {
+		messages = append(messages, *message)
+	}
+	return messages
+}
+
+// pages returns the contents of the pages the bot published in a channel.
+func (session *fakeChatSession) pages(channelID string) []string {
+	var pages []string
+	for _, message := range session.channelMessages(channelID) {
+		if message.Author.ID == fakeBotID && strings.HasPrefix(message.Content, "```") {
+			pages = append(pages, message.Content)
+		}
+	}
+	return pages
+}
+
+// publishedItems parses the list the bot published in a channel.
+func (session *fakeChatSession) publishedItems(channelID string) []model.PantryItem {
+	var items []model.PantryItem
+	for _, page := range session.pages(channelID) {
+		items = append(items, model.FromMarkdownTable(page, "")...)
+	}
+	return items
+}
+
+// lastResponse returns the latest interaction response.
+func (session *fakeChatSession) lastResponse() *discordgo.InteractionResponse {
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
+	if len(session.responses) == 0 {
+		return nil
+	}
+	return session.responses[len(session.responses)-1]
+}
+
+// componentInteraction creates the event of a user clicking a button or choosing from a select menu.
+func componentInteraction(channelID, author, customID string, values ...string) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		Type:      discordgo.InteractionMessageComponent,
+		ChannelID: channelID,
+		User:      &discordgo.User{ID: author, Username: author},
+		Data:      discordgo.MessageComponentInteractionData{CustomID: customID, Values: values},
+	}}
+}
+
+// modalInteraction creates the event of a user submitting a modal with a single text input.
+func modalInteraction(channelID, author, customID, value string) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		Type:      discordgo.InteractionModalSubmit,
+		ChannelID: channelID,
+		User:      &discordgo.User{ID: author, Username: author},
+		Data: discordgo.ModalSubmitInteractionData{
+			CustomID: customID,
+			Components: []discordgo.MessageComponent{
+				&discordgo.ActionsRow{Components: []discordgo.MessageComponent{&discordgo.TextInput{Value: value}}},
+			},
+		},
+	}}
+}
+
+// commandInteraction creates the event of a user running a slash command.
+func commandInteraction(channelID, author string, data discordgo.ApplicationCommandInteractionData) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		Type:      discordgo.InteractionApplicationCommand,
+		ChannelID: channelID,
+		User:      &discordgo.User{ID: author, Username: author},
+		Data:      data,
+	}}
+}
@@ -0,0 +1,477 @@
+package service
+
+import (
+	"fmt"
+	"github.com/bwmarrin/discordgo"
+	"github.com/maribowman/roastbeef-swag/app/config"
+	"github.com/maribowman/roastbeef-swag/app/model"
+	"github.com/rs/zerolog/log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	AddCommand    = "add"
+	RemoveCommand = "remove"
+	EditCommand   = "edit"
+	ClearCommand  = "clear"
+	ListCommand   = "list"
+	UndoCommand   = "undo"
+	StatsCommand  = "stats"
+	ExportCommand = "export"
+	ImportCommand = "import"
+
+	AddSubcommand    = "add"
+	ListSubcommand   = "list"
+	DeleteSubcommand = "delete"
+
+	ListOption     = "list"
+	ItemOption     = "item"
+	QuantityOption = "quantity"
+	NumberOption   = "number"
+	ToOption       = "to"
+	ExpiresOption  = "expires"
+	UnitOption     = "unit"
+	MineOption     = "mine"
+	FormatOption   = "format"
+	FileOption     = "file"
+	ModeOption     = "mode"
+
+	maxAutocompleteChoices = 25 // Discord limit
+)
+
+// CreateApplicationCommands defines all slash commands. Every command takes an optional list, so lists can also be
+// managed from other channels.
+func CreateApplicationCommands(lists []string) []*discordgo.ApplicationCommand {
+	minValue := 1.0
+	minQuantity := 0.01
+	var listChoices []*discordgo.ApplicationCommandOptionChoice
+	for _, list := range lists {
+		listChoices = append(listChoices, &discordgo.ApplicationCommandOptionChoice{Name: list, Value: list})
+	}
+	listOption := &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionString,
+		Name:        ListOption,
+		Description: "List to manage, defaults to the list of the current channel",
+		Choices:     listChoices,
+	}
+	numberOption := func(name, description string, required bool) *discordgo.ApplicationCommandOption {
+		return &discordgo.ApplicationCommandOption{
+			Type:         discordgo.ApplicationCommandOptionInteger,
+			Name:         name,
+			Description:  description,
+			Required:     required,
+			MinValue:     &minValue,
+			Autocomplete: true,
+		}
+	}
+	quantityOption := &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionNumber,
+		Name:        QuantityOption,
+		Description: "Quantity of the item",
+		MinValue:    &minQuantity,
+	}
+	var unitChoices []*discordgo.ApplicationCommandOptionChoice
+	for _, unit := range model.Units {
+		unitChoices = append(unitChoices, &discordgo.ApplicationCommandOptionChoice{Name: unit, Value: unit})
+	}
+	unitOption := &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionString,
+		Name:        UnitOption,
+		Description: "Unit of the quantity",
+		Choices:     unitChoices,
+	}
+	expiresOption := &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionString,
+		Name:        ExpiresOption,
+		Description: "Best-before date, e.g. 03.2027 or 31.03.27",
+	}
+
+	return []*discordgo.ApplicationCommand{
+		{
+			Name:        AddCommand,
+			Description: "Add an item",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:         discordgo.ApplicationCommandOptionString,
+					Name:         ItemOption,
+					Description:  "Item to add",
+					Required:     true,
+					Autocomplete: true,
+				},
+				quantityOption,
+				unitOption,
+				expiresOption,
+				listOption,
+			},
+		},
+		{
+			Name:        RemoveCommand,
+			Description: "Remove an item or a range of items",
+			Options: []*discordgo.ApplicationCommandOption{
+				numberOption(NumberOption, "Number of the (first) item to remove", true),
+				numberOption(ToOption, "Number of the last item to remove", false),
+				listOption,
+			},
+		},
+		{
+			Name:        EditCommand,
+			Description: "Edit an item",
+			Options: []*discordgo.ApplicationCommandOption{
+				numberOption(NumberOption, "Number of the item to edit", true),
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        ItemOption,
+					Description: "New item name",
+				},
+				quantityOption,
+				unitOption,
+				expiresOption,
+				listOption,
+			},
+		},
+		{
+			Name:        ClearCommand,
+			Description: "Remove all items",
+			Options:     []*discordgo.ApplicationCommandOption{listOption},
+		},
+		{
+			Name:        ListCommand,
+			Description: "Show all items",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionBoolean,
+					Name:        MineOption,
+					Description: "Show only the items added by you",
+				},
+				listOption,
+			},
+		},
+		{
+			Name:        UndoCommand,
+			Description: "Undo the last change",
+			Options:     []*discordgo.ApplicationCommandOption{listOption},
+		},
+		{
+			Name:        ScheduleCommand,
+			Description: "Add items to a list again and again",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        AddSubcommand,
+					Description: "Schedule an item",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        EntryOption,
+							Description: "Item and recurrence, e.g. `milk 2 every monday` or `toilet paper every 3 weeks`",
+							Required:    true,
+						},
+						listOption,
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        ListSubcommand,
+					Description: "Show all schedules",
+					Options:     []*discordgo.ApplicationCommandOption{listOption},
+				},
+				scheduleIDSubcommand(PauseSubcommand, "Pause a schedule", listOption),
+				scheduleIDSubcommand(ResumeSubcommand, "Resume a paused schedule", listOption),
+				scheduleIDSubcommand(DeleteSubcommand, "Delete a schedule", listOption),
+			},
+		},
+		{
+			Name:        RecipeCommand,
+			Description: "Manage the recipes added by typing `recipe <name> x<factor>`",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        AddSubcommand,
+					Description: "Save a recipe, an existing recipe of the same name is replaced",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        NameOption,
+							Description: "Name of the recipe, e.g. `lasagne`",
+							Required:    true,
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        IngredientsOption,
+							Description: "Ingredients for one batch, e.g. `500g minced meat; 2 onions; lasagne sheets`",
+							Required:    true,
+						},
+						listOption,
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        ListSubcommand,
+					Description: "Show all recipes",
+					Options:     []*discordgo.ApplicationCommandOption{listOption},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        DeleteSubcommand,
+					Description: "Delete a recipe",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        NameOption,
+							Description: "Name of the recipe",
+							Required:    true,
+						},
+						listOption,
+					},
+				},
+			},
+		},
+		{
+			Name:        StatsCommand,
+			Description: "Show the most removed items, how long items are kept and removals per week",
+			Options:     []*discordgo.ApplicationCommandOption{listOption},
+		},
+		{
+			Name:        ExportCommand,
+			Description: "Download the list as file",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        FormatOption,
+					Description: "File format, defaults to csv",
+					Choices:     stringChoices(ExportFormats),
+				},
+				listOption,
+			},
+		},
+		{
+			Name:        ImportCommand,
+			Description: "Add the items of a CSV, JSON or text file to the list or replace the list with them",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionAttachment,
+					Name:        FileOption,
+					Description: "File as downloaded by /export, or one item per line",
+					Required:    true,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        ModeOption,
+					Description: "Merge the items into the list or replace the whole list, defaults to merge",
+					Choices:     stringChoices(ImportModes),
+				},
+				listOption,
+			},
+		},
+	}
+}
+
+func stringChoices(values []string) []*discordgo.ApplicationCommandOptionChoice {
+	var choices []*discordgo.ApplicationCommandOptionChoice
+	for _, value := range values {
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: value, Value: value})
+	}
+	return choices
+}
+
+func scheduleIDSubcommand(name, description string, listOption *discordgo.ApplicationCommandOption) *discordgo.ApplicationCommandOption {
+	minValue := 1.0
+	return &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionSubCommand,
+		Name:        name,
+		Description: description,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        ScheduleIDOption,
+				Description: "ID of the schedule as shown by `/schedule list`",
+				Required:    true,
+				MinValue:    &minValue,
+			},
+			listOption,
+		},
+	}
+}
+
+// CommandOptions maps the options of a slash command by name. Options of a subcommand are mapped as well.
+func CommandOptions(data discordgo.ApplicationCommandInteractionData) map[string]*discordgo.ApplicationCommandInteractionDataOption {
+	options := map[string]*discordgo.ApplicationCommandInteractionDataOption{}
+	for _, option := range data.Options {
+		if option.Type == discordgo.ApplicationCommandOptionSubCommand {
+			for _, subcommandOption := range option.Options {
+				options[subcommandOption.Name] = subcommandOption
+			}
+			continue
+		}
+		options[option.Name] = option
+	}
+	return options
+}
+
+// ExecuteCommand applies a slash command to a list. It returns the updated list and a confirmation for the user.
+// Errors are meant to be shown to the user.
+func ExecuteCommand(data discordgo.ApplicationCommandInteractionData, pantryClient model.PantryClient, historyClient model.HistoryClient, author string) ([]model.PantryItem, string, error) {
+	items, err := pantryClient.GetItems()
+	if err != nil {
+		log.Error().Err(err).Msg("Could not load list")
+		return nil, "", fmt.Errorf("could not load list")
+	}
+
+	options := CommandOptions(data)
+	var expires time.Time
+	if option, ok := options[ExpiresOption]; ok {
+		if expires, err = ParseExpiry(strings.TrimSpace(option.StringValue())); err != nil {
+			return nil, "", err
+		}
+	}
+
+	updatedItems := items
+	switch data.Name {
+	case AddCommand:
+		amount, unit := 1.0, ""
+		if quantity, ok := options[QuantityOption]; ok {
+			amount = quantity.FloatValue()
+		}
+		if option, ok := options[UnitOption]; ok {
+			unit = option.StringValue()
+		}
+		updatedItems = MergeItem(updatedItems, model.PantryItem{
+			Item:    strings.TrimSpace(options[ItemOption].StringValue()),
+			Amount:  amount,
+			Unit:    unit,
+			Date:    time.Now().Truncate(time.Minute),
+			Expires: expires,
+		})
+	case RemoveCommand:
+		from := int(options[NumberOption].IntValue())
+		to := from
+		if option, ok := options[ToOption]; ok {
+			to = int(option.IntValue())
+		}
+		if from > to || to > len(items) {
+			return nil, "", fmt.Errorf("there is no item range %d-%d", from, to)
+		}
+		updatedItems = remove(items, fmt.Sprintf("%d-%d", from, to))
+	case EditCommand:
+		number := int(options[NumberOption].IntValue())
+		if number > len(items) {
+			return nil, "", fmt.Errorf("there is no item #%d", number)
+		}
+		updatedItems = append([]model.PantryItem{}, items...)
+		if item, ok := options[ItemOption]; ok && strings.TrimSpace(item.StringValue()) != "" {
+			updatedItems[number-1].Item = strings.TrimSpace(item.StringValue())
+		}
+		if quantity, ok := options[QuantityOption]; ok {
+			updatedItems[number-1].Amount = quantity.FloatValue()
+		}
+		if unit, ok := options[UnitOption]; ok {
+			updatedItems[number-1].Unit = unit.StringValue()
+		}
+		if !expires.IsZero() {
+			updatedItems[number-1].Expires = expires
+		}
+	case ClearCommand:
+		updatedItems = remove(items, "*")
+	case UndoCommand:
+		if updatedItems, err = RestoreRevision(pantryClient, historyClient.Undo); err != nil {
+			log.Error().Err(err).Msg("Could not restore list revision")
+			return nil, "", fmt.Errorf("could not undo last change")
+		}
+		return updatedItems, "Undid last change", nil
+	default:
+		return nil, "", fmt.Errorf("unknown command `%s`", data.Name)
+	}
+
+	if updatedItems, err = StoreItems(pantryClient, historyClient, items, updatedItems, author); err != nil {
+		log.Error().Err(err).Msg("Could not store list")
+		return nil, "", fmt.Errorf("could not store list")
+	}
+	reply := model.DescribeChanges(items, updatedItems)
+	if reply == "" {
+		reply = "nothing changed"
+	}
+	return updatedItems, strings.ToUpper(reply[:1]) + reply[1:], nil
+}
+
+// CreateAutocompleteResponse suggests existing items for the focused option. Item names are suggested by name,
+// item numbers are suggested with their name as label.
+func CreateAutocompleteResponse(data discordgo.ApplicationCommandInteractionData, items []model.PantryItem) *discordgo.InteractionResponse {
+	var choices []*discordgo.ApplicationCommandOptionChoice
+	for _, option := range data.Options {
+		if !option.Focused {
+			continue
+		}
+		input := strings.ToLower(fmt.Sprint(option.Value))
+
+		for _, item := range items {
+			if len(choices) == maxAutocompleteChoices {
+				break
+			}
+			switch option.Type {
+			case discordgo.ApplicationCommandOptionInteger:
+				label := fmt.Sprintf("%d - %s", item.Number, item.Item)
+				if input == "" || strings.HasPrefix(strconv.Itoa(item.Number), input) || strings.Contains(strings.ToLower(item.Item), input) {
+					choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: label, Value: item.Number})
+				}
+			default:
+				if strings.Contains(strings.ToLower(item.Item), input) && !containsChoice(choices, item.Item) {
+					choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: item.Item, Value: item.Item})
+				}
+			}
+		}
+	}
+
+	return &discordgo.InteractionResponse{
+		Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+		Data: &discordgo.InteractionResponseData{
+			Choices: choices,
+		},
+	}
+}
+
+func containsChoice(choices []*discordgo.ApplicationCommandOptionChoice, name string) bool {
+	for _, choice := range choices {
+		if choice.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateCommandResponse replies to a slash command only visible to the user who sent it.
+func CreateCommandResponse(content string) *discordgo.InteractionResponse {
+	return &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	}
+}
+
+// respondWithTable answers a command with a Markdown table only visible to the user. Tables exceeding the character
+// limit of a message are split into pages sent as follow-up messages.
+func respondWithTable(session model.ChatSession, interaction *discordgo.InteractionCreate, table string) {
+	pages := model.SplitMarkdownTable(table, maxMessageLength)
+	_ = session.InteractionRespond(interaction.Interaction, CreateCommandResponse(pages[0]))
+	for _, page := range pages[1:] {
+		if _, err := session.FollowupMessageCreate(interaction.Interaction, true, &discordgo.WebhookParams{
+			Content: page,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		}); err != nil {
+			log.Error().Err(err).Msg("Could not send follow-up message")
+		}
+	}
+}
+
+// RepublishItems updates the bot messages of a list channel after the list was changed outside of a message event.
+func RepublishItems(items []model.PantryItem, session model.ChatSession, channel config.Channel) {
+	pages, _, _, _, err := PreProcessMessageEvent(session, channel.ID)
+	if err != nil {
+		log.Error().Err(err).Msgf("Could not find bot messages of channel %s", channel.ID)
+		return
+	}
+	PublishItems(items, session, channel, PageIDs(pages))
+}
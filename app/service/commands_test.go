@@ -0,0 +1,56 @@
+package service
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/maribowman/roastbeef-swag/app/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCreateAutocompleteResponse(t *testing.T) {
+	// given
+	items := []model.PantryItem{
+		{Number: 1, Item: "eggs", Amount: 4},
+		{Number: 2, Item: "Coffee beans", Amount: 1},
+		{Number: 12, Item: "coffee", Amount: 1},
+	}
+	tests := map[string]struct {
+		option   *discordgo.ApplicationCommandInteractionDataOption
+		expected []*discordgo.ApplicationCommandOptionChoice
+	}{
+		"item by name": {
+			option: &discordgo.ApplicationCommandInteractionDataOption{Name: ItemOption, Type: discordgo.ApplicationCommandOptionString, Value: "coff", Focused: true},
+			expected: []*discordgo.ApplicationCommandOptionChoice{
+				{Name: "Coffee beans", Value: "Coffee beans"},
+				{Name: "coffee", Value: "coffee"},
+			},
+		},
+		"number by prefix": {
+			option: &discordgo.ApplicationCommandInteractionDataOption{Name: NumberOption, Type: discordgo.ApplicationCommandOptionInteger, Value: "1", Focused: true},
+			expected: []*discordgo.ApplicationCommandOptionChoice{
+				{Name: "1 - eggs", Value: 1},
+				{Name: "12 - coffee", Value: 12},
+			},
+		},
+		"number by name": {
+			option: &discordgo.ApplicationCommandInteractionDataOption{Name: NumberOption, Type: discordgo.ApplicationCommandOptionInteger, Value: "egg", Focused: true},
+			expected: []*discordgo.ApplicationCommandOptionChoice{
+				{Name: "1 - eggs", Value: 1},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// when
+			actual := CreateAutocompleteResponse(discordgo.ApplicationCommandInteractionData{
+				Name:    RemoveCommand,
+				Options: []*discordgo.ApplicationCommandInteractionDataOption{test.option},
+			}, items)
+
+			// then
+			assert.Equal(t, discordgo.InteractionApplicationCommandAutocompleteResult, actual.Type)
+			assert.EqualValues(t, test.expected, actual.Data.Choices)
+		})
+	}
+}
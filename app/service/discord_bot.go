@@ -6,11 +6,22 @@ import (
 	"github.com/maribowman/roastbeef-swag/app/config"
 	"github.com/maribowman/roastbeef-swag/app/model"
 	"github.com/rs/zerolog/log"
+	"sync/atomic"
 )
 
+// channelTypes maps the `type` of a configured channel to the constructor of its handler.
+var channelTypes = map[string]func(config.Channel, model.DatabaseClient, model.ListRegistry) model.BotHandler{
+	config.ListChannelType:     NewListHandler,
+	config.MealPlanChannelType: NewMealPlanHandler,
+}
+
 type DiscordBot struct {
-	session  *discordgo.Session
-	handlers map[string]model.BotHandler
+	session    *discordgo.Session
+	handlers   map[string]model.BotHandler
+	channelIDs map[string]string // list name -> channel ID
+	lists      []string          // names of the list channels, which slash commands can manage
+	connected  atomic.Bool       // the gateway is connected
+	ready      atomic.Bool       // the first ready event was handled
 }
 
 func NewDiscordBot(databaseClient model.DatabaseClient) model.DiscordBot {
@@ -19,25 +30,27 @@ func NewDiscordBot(databaseClient model.DatabaseClient) model.DiscordBot {
 		log.Fatal().Err(err).Msg("Error creating Discord session")
 	}
 
-	handlers := map[string]model.BotHandler{}
+	bot := DiscordBot{
+		session:    session,
+		handlers:   map[string]model.BotHandler{},
+		channelIDs: map[string]string{},
+	}
 	for _, channel := range config.Config.Discord.Channels {
-		switch channel.Name {
-		case GroceriesChannel:
-			handlers[channel.ID] = NewGroceryHandler(channel.ID, databaseClient, channel.LineBreak)
-			continue
-		case TkGoodsChannel:
-			handlers[channel.ID] = NewTkHandler(channel.ID, databaseClient, channel.LineBreak)
+		newHandler, ok := channelTypes[channel.Type]
+		if !ok {
+			log.Error().Msgf("Could not map channel `%s` of type `%s` to handler", channel.Name, channel.Type)
 			continue
 		}
-		log.Error().Msgf("Could not map channel `%s` to handler", channel.Name)
-	}
-
-	bot := DiscordBot{
-		session:  session,
-		handlers: handlers,
+		bot.handlers[channel.ID] = newHandler(channel, databaseClient, &bot)
+		bot.channelIDs[channel.Name] = channel.ID
+		if _, ok := bot.handlers[channel.ID].(model.ListHandler); ok {
+			bot.lists = append(bot.lists, channel.Name)
+		}
 	}
 
 	bot.session.AddHandler(bot.Ready)
+	bot.session.AddHandler(func(*discordgo.Session, *discordgo.Connect) { bot.connected.Store(true) })
+	bot.session.AddHandler(func(*discordgo.Session, *discordgo.Disconnect) { bot.connected.Store(false) })
 	bot.session.AddHandler(bot.MessageDispatch)
 	bot.session.AddHandler(bot.InteractionDispatch)
 
@@ -49,9 +62,14 @@ func NewDiscordBot(databaseClient model.DatabaseClient) model.DiscordBot {
 }
 
 func (bot *DiscordBot) Ready(session *discordgo.Session, ready *discordgo.Ready) {
+	if _, err := session.ApplicationCommandBulkOverwrite(ready.User.ID, "", CreateApplicationCommands(bot.lists)); err != nil {
+		log.Error().Err(err).Msg("Could not register slash commands")
+	}
 	for _, handler := range bot.handlers {
-		handler.ReadyEvent(session, ready)
+		handler.ReadyEvent(meteredSession{session}, ready)
 	}
+	bot.connected.Store(true)
+	bot.ready.Store(true)
 	log.Info().Msg("Bot is up!")
 }
 
@@ -61,17 +79,29 @@ func (bot *DiscordBot) MessageDispatch(session *discordgo.Session, message *disc
 	}
 
 	if handler, ok := bot.handlers[message.ChannelID]; ok {
-		handler.MessageEvent(session, message)
+		handler.MessageEvent(meteredSession{session}, message)
 	} else {
 		log.Error().Msgf("Could not match handler for message event on channel `%s`", message.ChannelID)
 	}
 }
 
 func (bot *DiscordBot) InteractionDispatch(session *discordgo.Session, interaction *discordgo.InteractionCreate) {
-	if handler, ok := bot.handlers[interaction.ChannelID]; ok {
+	handler, ok := bot.handlers[interaction.ChannelID]
+	isCommand := interaction.Type == discordgo.InteractionApplicationCommand || interaction.Type == discordgo.InteractionApplicationCommandAutocomplete
+	if isCommand {
+		// slash commands can manage any list from any channel
+		if option, found := CommandOptions(interaction.ApplicationCommandData())[ListOption]; found {
+			handler, ok = bot.handlers[bot.channelIDs[option.StringValue()]]
+		}
+	}
+
+	if ok {
+		session := meteredSession{session}
 		switch interaction.Type {
 		case discordgo.InteractionApplicationCommand:
-			// slash commands
+			handler.ApplicationCommandInteractionEvent(session, interaction)
+		case discordgo.InteractionApplicationCommandAutocomplete:
+			handler.ApplicationCommandAutocompleteInteractionEvent(session, interaction)
 		case discordgo.InteractionMessageComponent:
 			handler.MessageComponentInteractionEvent(session, interaction)
 		case discordgo.InteractionModalSubmit:
@@ -81,7 +111,25 @@ func (bot *DiscordBot) InteractionDispatch(session *discordgo.Session, interacti
 		}
 	} else {
 		log.Error().Msgf("Could not match handler for interaction event on channel `%s`", interaction.ChannelID)
+		if interaction.Type == discordgo.InteractionApplicationCommand {
+			_ = session.InteractionRespond(interaction.Interaction, CreateCommandResponse(fmt.Sprintf("Please choose a %s", ListOption)))
+		}
+	}
+}
+
+func (bot *DiscordBot) GetListHandlers() map[string]model.ListHandler {
+	listHandlers := map[string]model.ListHandler{}
+	for list, channelID := range bot.channelIDs {
+		if listHandler, ok := bot.handlers[channelID].(model.ListHandler); ok {
+			listHandlers[list] = listHandler
+		}
 	}
+	return listHandlers
+}
+
+// IsReady reports whether the gateway is connected and all handlers received the ready event.
+func (bot *DiscordBot) IsReady() bool {
+	return bot.connected.Load() && bot.ready.Load()
 }
 
 func (bot *DiscordBot) CloseSession() {
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"fmt"
+	"github.com/bwmarrin/discordgo"
+	"github.com/maribowman/roastbeef-swag/app/config"
+	"github.com/maribowman/roastbeef-swag/app/model"
+	"github.com/rs/zerolog/log"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	defaultShelfLife   = "default"
+	expiryDigestPrefix = "**Expiry digest for "
+)
+
+// CreateExpiryDigest lists all items which expired, expire within the configured warning period or are stored longer
+// than the shelf life of their category. The digest is empty if there is nothing to report.
+func CreateExpiryDigest(items []model.PantryItem, channel config.Channel, categories map[string][]string, now time.Time) string {
+	warnUntil := now.AddDate(0, 0, channel.Expiry.WarnDays)
+
+	var lines []string
+	for _, item := range items {
+		if !item.Expires.IsZero() && item.Expires.Before(now) {
+			lines = append(lines, fmt.Sprintf("❗ [%d] %s expired on %s", item.Number, item.Item, item.Expires.Format(model.ExpiryDateFormat)))
+			continue
+		} else if !item.Expires.IsZero() && item.Expires.Before(warnUntil) {
+			lines = append(lines, fmt.Sprintf("⚠️ [%d] %s expires on %s", item.Number, item.Item, item.Expires.Format(model.ExpiryDateFormat)))
+			continue
+		}
+
+		category := model.CategoryOf(item, categories)
+		shelfLife, ok := channel.Expiry.ShelfLife[category]
+		if !ok || category == "" {
+			shelfLife = channel.Expiry.ShelfLife[defaultShelfLife]
+		}
+		if shelfLife > 0 && item.Date.AddDate(0, 0, shelfLife).Before(now) {
+			lines = append(lines, fmt.Sprintf("🧊 [%d] %s stored since %s, longer than %d days", item.Number, item.Item, item.Date.Format(model.ExpiryDateFormat), shelfLife))
+		}
+	}
+
+	if len(lines) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s%s**\n%s", expiryDigestPrefix, channel.Name, strings.Join(lines, "\n"))
+}
+
+// ExpiryDigests returns the expiry digests the bot posted to a channel, oldest first.
+func ExpiryDigests(session model.ChatSession, channelID string) ([]*discordgo.Message, error) {
+	channelMessages, err := session.ChannelMessages(channelID, 100, "", "", "")
+	if err != nil {
+		return nil, err
+	}
+	var digests []*discordgo.Message
+	for _, message := range channelMessages {
+		if message.Author.ID == config.Config.Discord.BotID && strings.HasPrefix(message.Content, expiryDigestPrefix) {
+			digests = append(digests, message)
+		}
+	}
+	slices.Reverse(digests)
+	return digests, nil
+}
+
+// moveExpiryDigests posts the expiry digests of a channel again, so that they stay below pages added to the list
+// instead of splitting it.
+func moveExpiryDigests(session model.ChatSession, channelID string) {
+	digests, err := ExpiryDigests(session, channelID)
+	if err != nil {
+		log.Error().Err(err).Msgf("Could not find expiry digests of channel %s", channelID)
+		return
+	}
+	for _, digest := range digests {
+		if err := session.ChannelMessageDelete(channelID, digest.ID); err != nil {
+			log.Error().Err(err).Msg("Could not delete expiry digest")
+			continue
+		}
+		if _, err := session.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{Content: digest.Content}); err != nil {
+			log.Error().Err(err).Msg("Could not send expiry digest")
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"github.com/maribowman/roastbeef-swag/app/config"
+	"github.com/maribowman/roastbeef-swag/app/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestCreateExpiryDigest(t *testing.T) {
+	// given
+	now := time.Date(2026, 10, 18, 8, 0, 0, 0, time.Local)
+	channel := config.Channel{
+		Name: "tk",
+		Expiry: config.Expiry{
+			WarnDays:  7,
+			ShelfLife: map[string]int{"default": 180, "fish": 90},
+		},
+	}
+	categories := map[string][]string{"fish": {"salmon", "cod"}}
+	items := []model.PantryItem{
+		{Number: 1, Item: "salmon", Date: now.AddDate(0, 0, -10), Expires: now.AddDate(0, 0, -1)},
+		{Number: 2, Item: "cod", Date: now.AddDate(0, 0, -10), Expires: now.AddDate(0, 0, 3)},
+		{Number: 3, Item: "cod", Date: now.AddDate(0, 0, -91)},
+		{Number: 4, Item: "peas", Date: now.AddDate(0, 0, -91)},
+		{Number: 5, Item: "spinach", Date: now.AddDate(0, 0, -181)},
+		{Number: 6, Item: "salmon", Date: now.AddDate(0, 0, -91), Expires: now.AddDate(1, 0, 0)},
+	}
+
+	// when
+	digest := CreateExpiryDigest(items, channel, categories, now)
+
+	// then
+	assert.Equal(t, "**Expiry digest for tk**\n"+
+		"❗ [1] salmon expired on 17.10.26\n"+
+		"⚠️ [2] cod expires on 21.10.26\n"+
+		"🧊 [3] cod stored since 19.07.26, longer than 90 days\n"+
+		"🧊 [5] spinach stored since 20.04.26, longer than 180 days\n"+
+		"🧊 [6] salmon stored since 19.07.26, longer than 90 days", digest)
+	assert.Empty(t, CreateExpiryDigest(items[3:4], channel, categories, now))
+}
+
+func TestNextDailyRun(t *testing.T) {
+	// given
+	clock, _ := time.Parse("15:04", "08:00")
+
+	// then
+	assert.Equal(t, time.Date(2026, 10, 18, 8, 0, 0, 0, time.Local), nextDailyRun(time.Date(2026, 10, 18, 7, 59, 0, 0, time.Local), clock))
+	assert.Equal(t, time.Date(2026, 10, 19, 8, 0, 0, 0, time.Local), nextDailyRun(time.Date(2026, 10, 18, 8, 0, 0, 0, time.Local), clock))
+}
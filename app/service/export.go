@@ -0,0 +1,343 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/bwmarrin/discordgo"
+	"github.com/maribowman/roastbeef-swag/app/model"
+	"io"
+	"net/http"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	CSVFormat  = "csv"
+	JSONFormat = "json"
+	TextFormat = "text"
+
+	MergeMode   = "merge"
+	ReplaceMode = "replace"
+
+	MaxImportSize = 1 << 20
+
+	exportDateFormat = "2006-01-02"
+)
+
+var (
+	ExportFormats = []string{CSVFormat, JSONFormat, TextFormat}
+	ImportModes   = []string{MergeMode, ReplaceMode}
+
+	exportExtensions   = map[string]string{CSVFormat: ".csv", JSONFormat: ".json", TextFormat: ".txt"}
+	exportContentTypes = map[string]string{
+		CSVFormat:  "text/csv; charset=utf-8",
+		JSONFormat: "application/json; charset=utf-8",
+		TextFormat: "text/plain; charset=utf-8",
+	}
+	csvHeader = []string{"item", "amount", "unit", "date", "expires", "note", "category", "checked", "author"}
+
+	attachmentClient = &http.Client{Timeout: 10 * time.Second}
+)
+
+// exportedItem is an item as written to JSON files. Numbers are left out, as they change with every import.
+type exportedItem struct {
+	Item     string    `json:"item"`
+	Amount   float64   `json:"amount"`
+	Unit     string    `json:"unit,omitempty"`
+	Date     time.Time `json:"date"`
+	Expires  string    `json:"expires,omitempty"`
+	Note     string    `json:"note,omitempty"`
+	Category string    `json:"category,omitempty"`
+	Checked  bool      `json:"checked,omitempty"`
+	Author   string    `json:"author,omitempty"`
+}
+
+// ExportItems writes a list as CSV, JSON or plain text. Plain text uses the same lines as the edit modal, so it can
+// be pasted into the channel as well.
+func ExportItems(items []model.PantryItem, format string) ([]byte, error) {
+	switch format {
+	case CSVFormat:
+		var buffer bytes.Buffer
+		writer := csv.NewWriter(&buffer)
+		_ = writer.Write(csvHeader)
+		for _, item := range items {
+			_ = writer.Write([]string{
+				item.Item,
+				strconv.FormatFloat(item.Amount, 'f', -1, 64),
+				item.Unit,
+				item.Date.Format(time.RFC3339),
+				formatExportDate(item.Expires),
+				item.Note,
+				item.Category,
+				strconv.FormatBool(item.Checked),
+				item.Author,
+			})
+		}
+		writer.Flush()
+		return buffer.Bytes(), writer.Error()
+	case JSONFormat:
+		exported := make([]exportedItem, 0, len(items))
+		for _, item := range items {
+			exported = append(exported, exportedItem{
+				Item:     item.Item,
+				Amount:   item.Amount,
+				Unit:     item.Unit,
+				Date:     item.Date,
+				Expires:  formatExportDate(item.Expires),
+				Note:     item.Note,
+				Category: item.Category,
+				Checked:  item.Checked,
+				Author:   item.Author,
+			})
+		}
+		return json.MarshalIndent(exported, "", "  ")
+	case TextFormat:
+		if len(items) == 0 {
+			return []byte{}, nil
+		}
+		return []byte(model.ToList(items) + "\n"), nil
+	}
+	return nil, fmt.Errorf("unknown format `%s`, use one of %s", format, strings.Join(ExportFormats, ", "))
+}
+
+// ExportFileName names the export of a list, e.g. `tkGoods.csv`.
+func ExportFileName(list, format string) string {
+	return list + exportExtensions[format]
+}
+
+// ExportContentType returns the MIME type of an export format.
+func ExportContentType(format string) string {
+	return exportContentTypes[format]
+}
+
+// FormatOf detects the format of an import by the extension of its file name or else by its content type.
+// It returns an empty string for unknown formats.
+func FormatOf(fileName, contentType string) string {
+	extension := strings.ToLower(path.Ext(fileName))
+	for format, formatExtension := range exportExtensions {
+		if extension == formatExtension {
+			return format
+		}
+	}
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	for format, formatContentType := range exportContentTypes {
+		if formatMediaType, _, _ := strings.Cut(formatContentType, ";"); strings.TrimSpace(mediaType) == formatMediaType {
+			return format
+		}
+	}
+	return ""
+}
+
+// ImportItems reads items written by ExportItems. CSV files need a header naming at least the `item` column, other
+// columns are optional and unknown ones ignored. Items without amount count as one, items without date are added
+// today.
+func ImportItems(data []byte, format string) ([]model.PantryItem, error) {
+	var items []model.PantryItem
+	var err error
+	switch format {
+	case CSVFormat:
+		items, err = importCSV(data)
+	case JSONFormat:
+		items, err = importJSON(data)
+	case TextFormat:
+		items = importText(string(data))
+	default:
+		return nil, fmt.Errorf("unknown format `%s`, use one of %s", format, strings.Join(ExportFormats, ", "))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	today := time.Now().Truncate(time.Minute)
+	for index := range items {
+		items[index].Number = index + 1
+		if items[index].Date.IsZero() {
+			items[index].Date = today
+		}
+	}
+	return items, nil
+}
+
+func importCSV(data []byte) ([]model.PantryItem, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	columns := map[string]int{}
+	for index, column := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff")))] = index
+	}
+	if _, ok := columns["item"]; !ok {
+		return nil, errors.New("CSV needs a header with an `item` column")
+	}
+
+	var items []model.PantryItem
+	for line, record := range records[1:] {
+		field := func(column string) string {
+			if index, ok := columns[column]; ok && index < len(record) {
+				return strings.TrimSpace(record[index])
+			}
+			return ""
+		}
+		item, err := importItem(exportedItem{
+			Item:     field("item"),
+			Unit:     field("unit"),
+			Expires:  field("expires"),
+			Note:     field("note"),
+			Category: field("category"),
+			Author:   field("author"),
+		})
+		if err == nil && field("amount") != "" {
+			if item.Amount, err = strconv.ParseFloat(strings.ReplaceAll(field("amount"), ",", "."), 64); err == nil && item.Amount <= 0 {
+				err = fmt.Errorf("amount must be positive")
+			}
+		}
+		if err == nil && field("date") != "" {
+			item.Date, err = parseExportDate(field("date"))
+		}
+		if err == nil && field("checked") != "" {
+			item.Checked, err = strconv.ParseBool(field("checked"))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line+2, err)
+		}
+		if item.Item != "" {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+func importJSON(data []byte) ([]model.PantryItem, error) {
+	var exported []exportedItem
+	if err := json.Unmarshal(data, &exported); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	var items []model.PantryItem
+	for index, entry := range exported {
+		item, err := importItem(entry)
+		if err == nil && entry.Amount < 0 {
+			err = fmt.Errorf("amount must be positive")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %w", index+1, err)
+		}
+		if entry.Amount > 0 {
+			item.Amount = entry.Amount
+		}
+		item.Date = entry.Date
+		item.Checked = entry.Checked
+		if item.Item != "" {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+// importItem converts the fields which CSV and JSON share.
+func importItem(entry exportedItem) (model.PantryItem, error) {
+	item := model.PantryItem{
+		Item:     strings.TrimSpace(entry.Item),
+		Amount:   1,
+		Unit:     model.NormalizeUnit(entry.Unit),
+		Note:     strings.TrimSpace(entry.Note),
+		Category: strings.ToLower(strings.TrimSpace(entry.Category)),
+		Author:   strings.TrimSpace(entry.Author),
+	}
+	if item.Unit != "" && !slices.Contains(model.Units, item.Unit) {
+		return item, fmt.Errorf("unknown unit `%s`", entry.Unit)
+	}
+	if entry.Expires != "" {
+		expires, err := parseExportDate(entry.Expires)
+		if err != nil {
+			return item, err
+		}
+		item.Expires = expires
+	}
+	return item, nil
+}
+
+// importText reads one item per line like typed into the channel. Numbers like `[3]` from exports are skipped.
+func importText(text string) []model.PantryItem {
+	var items []model.PantryItem
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(NumberPrefixRegex.ReplaceAllString(strings.TrimSpace(line), ""))
+		if line == "" {
+			continue
+		}
+		items = add(items, line, time.Time{})
+	}
+	return items
+}
+
+// ApplyImport merges imported items into a list like items added in the channel, or replaces the whole list.
+func ApplyImport(items, imported []model.PantryItem, mode string) ([]model.PantryItem, error) {
+	switch mode {
+	case MergeMode:
+		for _, item := range imported {
+			items = MergeItem(items, item)
+		}
+		return items, nil
+	case ReplaceMode:
+		return slices.Clone(imported), nil
+	}
+	return nil, fmt.Errorf("unknown mode `%s`, use one of %s", mode, strings.Join(ImportModes, ", "))
+}
+
+// DownloadAttachment loads a file attached to a Discord message, up to MaxImportSize.
+func DownloadAttachment(attachment *discordgo.MessageAttachment) ([]byte, error) {
+	if attachment.Size > MaxImportSize {
+		return nil, fmt.Errorf("`%s` is larger than %d KiB", attachment.Filename, MaxImportSize>>10)
+	}
+	response, err := attachmentClient.Get(attachment.URL)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not download `%s`: %s", attachment.Filename, response.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(response.Body, MaxImportSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxImportSize {
+		return nil, fmt.Errorf("`%s` is larger than %d KiB", attachment.Filename, MaxImportSize>>10)
+	}
+	return data, nil
+}
+
+func formatExportDate(date time.Time) string {
+	if date.IsZero() {
+		return ""
+	}
+	return date.Format(exportDateFormat)
+}
+
+// parseExportDate accepts ISO dates and timestamps as well as the best-before dates of the channel.
+func parseExportDate(value string) (time.Time, error) {
+	if date, err := time.ParseInLocation(exportDateFormat, value, time.Local); err == nil {
+		return date, nil
+	}
+	if date, err := time.Parse(time.RFC3339, value); err == nil {
+		return date, nil
+	}
+	if date, err := ParseExpiry(value); err == nil {
+		return date, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid date `%s`", value)
+}
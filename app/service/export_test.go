@@ -0,0 +1,192 @@
+package service
+
+import (
+	"github.com/maribowman/roastbeef-swag/app/model"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportAndImportItems(t *testing.T) {
+	// given
+	date := time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC)
+	items := []model.PantryItem{
+		{ID: 1, Number: 1, Item: "salmon, smoked", Amount: 2, Date: date, Expires: time.Date(2027, 3, 31, 0, 0, 0, 0, time.Local), Category: "fish", Author: "mari"},
+		{ID: 2, Number: 2, Item: "peas", Amount: 0.5, Unit: "kg", Date: date, Note: "for soup", Checked: true, Author: "api"},
+	}
+
+	for _, format := range ExportFormats {
+		t.Run(format, func(t *testing.T) {
+			// when
+			export, err := ExportItems(items, format)
+			assert.NoError(t, err)
+			imported, err := ImportItems(export, format)
+
+			// then
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"[1] 2 salmon, smoked exp 31.03.27 #fish", "[2] 0.5 kg peas"}, listLines(imported))
+			assert.Equal(t, []int{1, 2}, []int{imported[0].Number, imported[1].Number})
+			assert.Zero(t, imported[0].ID, "imported items are new items")
+			if format != TextFormat {
+				assert.True(t, imported[0].Date.Equal(date))
+				assert.Equal(t, "mari", imported[0].Author)
+				assert.Equal(t, "for soup", imported[1].Note)
+				assert.True(t, imported[1].Checked)
+			}
+		})
+	}
+}
+
+func TestImportItems(t *testing.T) {
+	// given
+	tests := map[string]struct {
+		format        string
+		data          string
+		expected      []string
+		expectedError string
+	}{
+		"csv with only a header": {
+			format: CSVFormat,
+			data:   "item,amount\n",
+		},
+		"csv in any column order": {
+			format:   CSVFormat,
+			data:     "\ufeffexpires,unit,item,amount\n2027-01-31,pack,fish sticks,2\n,,eggs,\n",
+			expected: []string{"[1] 2 packs fish sticks exp 31.01.27", "[2] 1 eggs"},
+		},
+		"csv without item column": {
+			format:        CSVFormat,
+			data:          "name,amount\nmilk,1\n",
+			expectedError: "CSV needs a header with an `item` column",
+		},
+		"csv with invalid amount": {
+			format:        CSVFormat,
+			data:          "item,amount\nmilk,1\neggs,-3\n",
+			expectedError: "line 3: amount must be positive",
+		},
+		"json without optional fields": {
+			format:   JSONFormat,
+			data:     `[{"item": "ice cream"}, {"item": "pizza", "amount": 4, "expires": "05.2027"}]`,
+			expected: []string{"[1] 1 ice cream", "[2] 4 pizza exp 31.05.27"},
+		},
+		"json with unknown unit": {
+			format:        JSONFormat,
+			data:          `[{"item": "ice cream", "unit": "scoops"}]`,
+			expectedError: "item 1: unknown unit `scoops`",
+		},
+		"invalid json": {
+			format:        JSONFormat,
+			data:          `{"item": "ice cream"}`,
+			expectedError: "invalid JSON",
+		},
+		"text as typed into the channel": {
+			format:   TextFormat,
+			data:     "[1] 2 pizza\n\nice cream exp 12.2026 #dessert\r\n500g peas\n",
+			expected: []string{"[1] 2 pizza", "[2] 1 ice cream exp 31.12.26 #dessert", "[3] 500 g peas"},
+		},
+		"unknown format": {
+			format:        "xml",
+			expectedError: "unknown format `xml`",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// when
+			actual, err := ImportItems([]byte(test.data), test.format)
+
+			// then
+			if test.expectedError != "" {
+				assert.ErrorContains(t, err, test.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, listLines(actual))
+			for _, item := range actual {
+				assert.False(t, item.Date.IsZero())
+			}
+		})
+	}
+}
+
+func TestApplyImport(t *testing.T) {
+	// given
+	items := []model.PantryItem{
+		{ID: 1, Number: 1, Item: "pizza", Amount: 2},
+		{ID: 2, Number: 2, Item: "peas", Amount: 1},
+	}
+	imported := []model.PantryItem{
+		{Number: 1, Item: "pizzas", Amount: 3},
+		{Number: 2, Item: "ice cream", Amount: 1},
+	}
+	tests := map[string]struct {
+		mode          string
+		expected      []string
+		expectedError string
+	}{
+		"merge": {
+			mode:     MergeMode,
+			expected: []string{"[1] 5 pizza", "[2] 1 peas", "[3] 1 ice cream"},
+		},
+		"replace": {
+			mode:     ReplaceMode,
+			expected: []string{"[1] 3 pizzas", "[2] 1 ice cream"},
+		},
+		"unknown mode": {
+			mode:          "append",
+			expectedError: "unknown mode `append`",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// when
+			actual, err := ApplyImport(items, imported, test.mode)
+
+			// then
+			if test.expectedError != "" {
+				assert.ErrorContains(t, err, test.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, listLines(actual))
+			assert.Equal(t, "pizza", items[0].Item, "the original list is kept for the history")
+			assert.Equal(t, 2.0, items[0].Amount)
+		})
+	}
+}
+
+func TestFormatOf(t *testing.T) {
+	// given
+	tests := map[string]struct {
+		fileName    string
+		contentType string
+		expected    string
+	}{
+		"csv file":             {fileName: "tkGoods.CSV", expected: CSVFormat},
+		"json file":            {fileName: "backup.json", contentType: "text/plain", expected: JSONFormat},
+		"text file":            {fileName: "list.txt", expected: TextFormat},
+		"content type":         {contentType: "application/json; charset=utf-8", expected: JSONFormat},
+		"unknown file":         {fileName: "list.xlsx"},
+		"unknown content type": {contentType: "application/octet-stream"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// when
+			actual := FormatOf(test.fileName, test.contentType)
+
+			// then
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+// listLines renders items like the plain text export, one line per item.
+func listLines(items []model.PantryItem) []string {
+	if len(items) == 0 {
+		return nil
+	}
+	return strings.Split(model.ToList(items), "\n")
+}
@@ -0,0 +1,906 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/bwmarrin/discordgo"
+	"github.com/maribowman/roastbeef-swag/app/config"
+	"github.com/maribowman/roastbeef-swag/app/metrics"
+	"github.com/maribowman/roastbeef-swag/app/model"
+	"github.com/maribowman/roastbeef-swag/app/repository"
+	"github.com/rs/zerolog/log"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	EditBehaviour     = "edit"
+	UndoBehaviour     = "undo"
+	HistoryBehaviour  = "history"
+	MoveBehaviour     = "move"
+	ShoppingBehaviour = "shopping"
+	SuggestBehaviour  = "suggest"
+)
+
+var defaultBehaviours = []string{EditBehaviour, UndoBehaviour, HistoryBehaviour}
+
+// ListHandler manages a list channel. Everything that differs between lists, like the database table, the rendered
+// columns or the available buttons, is taken from the channel config. Discord events, API calls and schedules all
+// arrive on goroutines of their own, so every change of the list runs on the actor of the channel.
+type ListHandler struct {
+	actor             *actor
+	deferred          []func() // work on other lists, run once the actor is released
+	session           model.ChatSession
+	channel           config.Channel
+	pantryClient      model.PantryClient
+	historyClient     model.HistoryClient
+	removalClient     model.RemovalClient
+	scheduleClient    model.ScheduleClient
+	recipeClient      model.RecipeClient
+	lists             model.ListRegistry
+	digestSchedule    sync.Once
+	recurringSchedule sync.Once
+}
+
+func NewListHandler(channel config.Channel, databaseClient model.DatabaseClient, lists model.ListRegistry) model.BotHandler {
+	log.Debug().Msgf("Registering list handler for `%s`", channel.Name)
+	if len(channel.Columns) == 0 {
+		channel.Columns = model.DefaultColumns
+	}
+	if channel.Behaviours == nil {
+		channel.Behaviours = defaultBehaviours
+	}
+	for _, column := range channel.Columns {
+		if !model.IsColumn(column) {
+			log.Fatal().Msgf("Unknown column `%s` in channel `%s`", column, channel.Name)
+		}
+	}
+	if !slices.Contains(channel.Columns, model.NumberColumn) || !slices.Contains(channel.Columns, model.ItemColumn) {
+		log.Fatal().Msgf("Columns of channel `%s` must contain `%s` and `%s`", channel.Name, model.NumberColumn, model.ItemColumn)
+	}
+
+	scheduleClient := repository.NewScheduleSqliteClient(databaseClient, channel.Table)
+	if err := SyncSchedules(scheduleClient, channel.Recurring, time.Now()); err != nil {
+		log.Fatal().Err(err).Msgf("Could not schedule recurring items of channel `%s`", channel.Name)
+	}
+
+	return &ListHandler{
+		actor:          newActor(),
+		channel:        channel,
+		pantryClient:   newAislePantryClient(repository.NewPantrySqliteClient(databaseClient, channel.Table), channel.Aisles),
+		historyClient:  repository.NewHistorySqliteClient(databaseClient, channel.Table),
+		removalClient:  repository.NewRemovalSqliteClient(databaseClient, channel.Table),
+		scheduleClient: scheduleClient,
+		recipeClient:   repository.NewRecipeSqliteClient(databaseClient),
+		lists:          lists,
+	}
+}
+
+func (handler *ListHandler) GetItems() ([]model.PantryItem, error) {
+	return handler.pantryClient.GetItems()
+}
+
+func (handler *ListHandler) GetRemovals(since time.Time) ([]model.Removal, error) {
+	return handler.removalClient.GetRemovals(since)
+}
+
+func (handler *ListHandler) ChangeItems(author string, change func([]model.PantryItem) ([]model.PantryItem, error)) (updatedItems []model.PantryItem, err error) {
+	handler.serialize(func() {
+		updatedItems, err = handler.changeItems(author, change)
+	})
+	return updatedItems, err
+}
+
+func (handler *ListHandler) changeItems(author string, change func([]model.PantryItem) ([]model.PantryItem, error)) ([]model.PantryItem, error) {
+	items, err := handler.pantryClient.GetItems()
+	if err != nil {
+		return nil, err
+	}
+	updatedItems, err := change(items)
+	if err != nil {
+		return nil, err
+	}
+	if updatedItems, err = StoreItems(handler.pantryClient, handler.historyClient, items, updatedItems, author); err != nil {
+		return nil, err
+	}
+	handler.recordChanges(items, updatedItems, author)
+	handler.republish(updatedItems)
+	handler.restock(items, updatedItems)
+	return updatedItems, nil
+}
+
+// MoveItems removes the items with the given IDs from the list and adds them to the target list in one transaction.
+// Quantities and best-before dates are kept, the added date is reset and ticks and notes are cleared.
+func (handler *ListHandler) MoveItems(author string, ids []int, target model.ListHandler) (moved []model.PantryItem, err error) {
+	targetHandler, ok := target.(*ListHandler)
+	if !ok || targetHandler == handler {
+		return nil, fmt.Errorf("items cannot be moved to this list")
+	}
+	handler.serialize(func() {
+		var oldItems, items, targetItems []model.PantryItem
+		if oldItems, err = handler.pantryClient.GetItems(); err != nil {
+			return
+		}
+		if items, targetItems, moved, err = handler.moveItems(author, ids, target); err != nil {
+			return
+		}
+		handler.republish(items)
+		targetHandler.republish(targetItems)
+		handler.restock(oldItems, items)
+	}, targetHandler)
+	return moved, err
+}
+
+// serialize runs the task on the actor of the list and, for moves, on the actors of the other lists involved. Work
+// the task defers with later runs after all actors are released, so a list never waits for another one while
+// holding its own actor.
+func (handler *ListHandler) serialize(task func(), others ...*ListHandler) {
+	actors := []*actor{handler.actor}
+	for _, other := range others {
+		actors = append(actors, other.actor)
+	}
+	var deferred []func()
+	func() {
+		release := holdAll(actors...)
+		defer release()
+		task()
+		deferred, handler.deferred = handler.deferred, nil
+	}()
+	for _, work := range deferred {
+		work()
+	}
+}
+
+// later defers work, which needs the actor of another list, until the running task released the actor of this list.
+func (handler *ListHandler) later(work func()) {
+	handler.deferred = append(handler.deferred, work)
+}
+
+func (handler *ListHandler) moveItems(author string, ids []int, target model.ListHandler) (items, targetItems, moved []model.PantryItem, err error) {
+	targetHandler, ok := target.(*ListHandler)
+	if !ok || targetHandler == handler {
+		return nil, nil, nil, fmt.Errorf("items cannot be moved to this list")
+	}
+	oldItems, err := handler.pantryClient.GetItems()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	oldTargetItems, err := targetHandler.pantryClient.GetItems()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var remaining []model.PantryItem
+	targetItems = oldTargetItems
+	for _, item := range oldItems {
+		if !slices.Contains(ids, item.ID) {
+			remaining = append(remaining, item)
+			continue
+		}
+		moved = append(moved, item)
+		item.ID = 0 // IDs are only unique per list
+		item.Date = time.Now().Truncate(time.Minute)
+		// Ticks and notes like `auto-added` or reservations describe the item on this list only.
+		item.Checked = false
+		item.Note = ""
+		targetItems = MergeItem(targetItems, item)
+	}
+	if len(moved) == 0 {
+		return nil, nil, nil, fmt.Errorf("there are no items to move")
+	}
+
+	if items, targetItems, err = handler.pantryClient.MoveItems(remaining, targetHandler.pantryClient, targetItems); err != nil {
+		return nil, nil, nil, err
+	}
+	RecordRevision(handler.historyClient, oldItems, items, author)
+	RecordRevision(targetHandler.historyClient, oldTargetItems, targetItems, author)
+	reason := model.MovedReason
+	if handler.channel.HasBehaviour(ShoppingBehaviour) {
+		reason = model.PurchasedReason // bought and stored right away, e.g. frozen
+	}
+	removals := CollectRemovals(moved, nil, author, time.Now())
+	for index := range removals {
+		if removals[index].Reason != model.PurchasedReason {
+			removals[index].Reason = reason
+		}
+	}
+	metrics.ItemsRemoved.WithLabelValues(handler.channel.Name).Add(float64(len(moved)))
+	metrics.ItemsAdded.WithLabelValues(targetHandler.channel.Name).Add(float64(len(moved)))
+	if err := handler.removalClient.AddRemovals(removals); err != nil {
+		log.Error().Err(err).Msgf("Could not record items moved from list `%s`", handler.channel.Name)
+	}
+	return items, targetItems, moved, nil
+}
+
+// moveByExpression moves items typed like `> 3 5-7 tk` into the channel. The items are selected right away, as the
+// numbers refer to the published list, but moved once the list is released, as the move holds the target list too.
+func (handler *ListHandler) moveByExpression(author string, items []model.PantryItem, expression, targetName string) error {
+	target, ok := handler.findList(targetName)
+	if !ok {
+		return fmt.Errorf("there is no list `%s`", targetName)
+	}
+	ids, err := SelectItemIDs(items, expression)
+	if err != nil {
+		return err
+	}
+	handler.later(func() {
+		if _, err := handler.MoveItems(author, ids, target); err != nil {
+			log.Warn().Err(err).Msgf("Could not move `%s` from list `%s` to `%s`", expression, handler.channel.Name, targetName)
+		}
+	})
+	return nil
+}
+
+func (handler *ListHandler) findList(name string) (model.ListHandler, bool) {
+	return findList(handler.lists, name)
+}
+
+// findList resolves a list channel by its name or table, e.g. `tkGoods` or `tk`.
+func findList(lists model.ListRegistry, name string) (model.ListHandler, bool) {
+	for listName, list := range lists.GetListHandlers() {
+		if strings.EqualFold(listName, name) {
+			return list, true
+		}
+		if listHandler, ok := list.(*ListHandler); ok && strings.EqualFold(listHandler.channel.Table, name) {
+			return list, true
+		}
+	}
+	return nil, false
+}
+
+// moveTargets lists the names of all other list channels, which items can be moved to.
+func (handler *ListHandler) moveTargets() []string {
+	var targets []string
+	for listName, list := range handler.lists.GetListHandlers() {
+		if listHandler, ok := list.(*ListHandler); ok && listHandler != handler {
+			targets = append(targets, listName)
+		}
+	}
+	slices.Sort(targets)
+	return targets
+}
+
+// restock adds items, whose stock fell below the minimum of a restock rule by the change, to the restock list.
+func (handler *ListHandler) restock(items, updatedItems []model.PantryItem) {
+	restockItems := RestockItems(handler.channel.Restock, items, updatedItems, config.Config.Discord.Categories, config.Config.Discord.Synonyms)
+	if len(restockItems) == 0 {
+		return
+	}
+	target, ok := handler.findList(handler.channel.Restock.List)
+	if !ok {
+		log.Error().Msgf("Could not find restock list `%s` of list `%s`", handler.channel.Restock.List, handler.channel.Name)
+		return
+	}
+	handler.later(func() {
+		if _, err := target.ChangeItems(restockActor, func(items []model.PantryItem) ([]model.PantryItem, error) {
+			return AddRestockItems(items, restockItems, config.Config.Discord.Synonyms), nil
+		}); err != nil {
+			log.Error().Err(err).Msgf("Could not restock list `%s`", handler.channel.Restock.List)
+		}
+	})
+}
+
+// finishTrip clears all ticked items from the list, which are recorded as purchased.
+func (handler *ListHandler) finishTrip(author string) ([]model.PantryItem, error) {
+	var bought []model.PantryItem
+	_, err := handler.changeItems(author, func(items []model.PantryItem) ([]model.PantryItem, error) {
+		var remaining []model.PantryItem
+		remaining, bought = FinishTrip(items)
+		return remaining, nil
+	})
+	return bought, err
+}
+
+// addRecurringItems adds the items of all due schedules to the list.
+func (handler *ListHandler) addRecurringItems() {
+	schedules, err := handler.scheduleClient.GetSchedules()
+	if err != nil {
+		log.Error().Err(err).Msgf("Could not load schedules of list `%s`", handler.channel.Name)
+		return
+	}
+	now := time.Now()
+	if !slices.ContainsFunc(schedules, func(schedule model.Schedule) bool { return isDue(schedule, now) }) {
+		return
+	}
+
+	var due []model.Schedule
+	if _, err := handler.ChangeItems(recurringActor, func(items []model.PantryItem) ([]model.PantryItem, error) {
+		items, due = AddRecurringItems(items, schedules, now)
+		return items, nil
+	}); err != nil {
+		log.Error().Err(err).Msgf("Could not add recurring items to list `%s`", handler.channel.Name)
+		return
+	}
+	for _, schedule := range due {
+		if err := handler.scheduleClient.UpdateSchedule(schedule); err != nil {
+			log.Error().Err(err).Msgf("Could not reschedule `%s` of list `%s`", schedule.Item, handler.channel.Name)
+		}
+	}
+}
+
+// addRecipe adds the missing ingredients of a recipe to the items. The stock lists of the channel are checked first.
+func (handler *ListHandler) addRecipe(items []model.PantryItem, request recipeRequest) ([]model.PantryItem, error) {
+	stored, err := handler.recipeClient.GetRecipes()
+	if err != nil {
+		return items, err
+	}
+	recipe, ok := FindRecipe(MergeRecipes(config.Config.Discord.Recipes, stored), request.name)
+	if !ok {
+		return items, fmt.Errorf("there is no recipe `%s`", request.name)
+	}
+
+	var stock []model.PantryItem
+	for _, name := range handler.channel.Stock {
+		list, ok := handler.findList(name)
+		if !ok {
+			return items, fmt.Errorf("there is no stock list `%s`", name)
+		}
+		stockItems, err := list.GetItems()
+		if err != nil {
+			return items, err
+		}
+		stock = append(stock, stockItems...)
+	}
+	return AddRecipe(items, recipe, request.factor, stock, config.Config.Discord.Synonyms), nil
+}
+
+// suggestions returns the items which are due to be bought again by the purchase cadence of the list.
+func (handler *ListHandler) suggestions() ([]model.Suggestion, error) {
+	items, err := handler.pantryClient.GetItems()
+	if err != nil {
+		return nil, err
+	}
+	removals, err := handler.removalClient.GetRemovals(time.Now().AddDate(0, -cadenceMonths, 0))
+	if err != nil {
+		return nil, err
+	}
+	return SuggestItems(removals, items, config.Config.Discord.Synonyms, time.Now()), nil
+}
+
+// recordChanges logs all items which left the list with a change and counts the added and removed items.
+func (handler *ListHandler) recordChanges(items, updatedItems []model.PantryItem, author string) {
+	handler.recordRemovals(items, updatedItems, CollectRemovals(items, updatedItems, author, time.Now()))
+}
+
+// recordCorrections is like recordChanges, but all removals except purchases count as corrections, as in the edit
+// modal items are removed to fix the list rather than because they were used up.
+func (handler *ListHandler) recordCorrections(items, updatedItems []model.PantryItem, author string) {
+	removals := CollectRemovals(items, updatedItems, author, time.Now())
+	for index := range removals {
+		if removals[index].Reason == model.ClearedReason {
+			removals[index].Reason = model.RemovedReason
+		}
+	}
+	handler.recordRemovals(items, updatedItems, removals)
+}
+
+func (handler *ListHandler) recordRemovals(items, updatedItems []model.PantryItem, removals []model.Removal) {
+	metrics.ItemsAdded.WithLabelValues(handler.channel.Name).Add(float64(countAddedItems(items, updatedItems)))
+	metrics.ItemsRemoved.WithLabelValues(handler.channel.Name).Add(float64(len(removals)))
+	if len(removals) == 0 {
+		return
+	}
+	if err := handler.removalClient.AddRemovals(removals); err != nil {
+		log.Error().Err(err).Msgf("Could not record items removed from list `%s`", handler.channel.Name)
+	}
+}
+
+// countAddedItems counts the stored items which were not on the list before.
+func countAddedItems(items, storedItems []model.PantryItem) int {
+	added := 0
+	for _, item := range storedItems {
+		if !slices.ContainsFunc(items, func(existing model.PantryItem) bool { return existing.ID == item.ID }) {
+			added++
+		}
+	}
+	return added
+}
+
+// republish publishes a list which was changed outside of a message event of its channel.
+func (handler *ListHandler) republish(items []model.PantryItem) {
+	if handler.session != nil {
+		RepublishItems(items, handler.session, handler.channel)
+	} else {
+		log.Warn().Msgf("List `%s` changed before bot was ready, publishing with next event", handler.channel.Name)
+	}
+}
+
+func (handler *ListHandler) ReadyEvent(session model.ChatSession, ready *discordgo.Ready) {
+	loaded := false
+	handler.serialize(func() {
+		handler.session = session
+		pages, _, _, _, err := PreProcessMessageEvent(session, handler.channel.ID)
+		if err != nil {
+			log.Error().Err(err).Msg("Error while processing message event")
+			return
+		}
+		items, err := LoadItems(handler.pantryClient, pages, handler.channel.DateFormat)
+		if err != nil {
+			log.Error().Err(err).Msgf("Could not load list `%s`", handler.channel.Name)
+			return
+		}
+		if err := InitHistory(handler.historyClient, items); err != nil {
+			log.Error().Err(err).Msgf("Could not initialize history of list `%s`", handler.channel.Name)
+		}
+		loaded = true
+	})
+	if !loaded {
+		return
+	}
+	handler.MessageEvent(session, &discordgo.MessageCreate{Message: &discordgo.Message{Author: &discordgo.User{ID: "init"}}})
+	if handler.channel.Expiry.Digest != "" {
+		handler.digestSchedule.Do(func() { // ready fires again on every reconnect
+			if err := scheduleDaily(handler.channel.Expiry.Digest, handler.postExpiryDigest); err != nil {
+				log.Error().Err(err).Msgf("Could not schedule expiry digest of list `%s`", handler.channel.Name)
+			}
+		})
+	}
+	handler.recurringSchedule.Do(func() {
+		scheduleEvery(recurringInterval, handler.addRecurringItems)
+	})
+	log.Debug().Msgf("Initialized list handler for `%s`", handler.channel.Name)
+}
+
+func (handler *ListHandler) MessageEvent(session model.ChatSession, message *discordgo.MessageCreate) {
+	handler.serialize(func() {
+		handler.messageEvent(session, message)
+	})
+}
+
+func (handler *ListHandler) messageEvent(session model.ChatSession, message *discordgo.MessageCreate) {
+	pages, content, authors, removableMessageIDs, err := PreProcessMessageEvent(session, handler.channel.ID)
+	if err != nil {
+		log.Error().Err(err).Msg("Error while processing message event")
+		return
+	}
+
+	initialItems, err := handler.pantryClient.GetItems()
+	if err != nil {
+		log.Error().Err(err).Msgf("Could not load list `%s`", handler.channel.Name)
+		return
+	}
+
+	// moves refer to the numbers of the published list, so their items are selected before any other change
+	moves, content := splitMoves(content)
+	for _, move := range moves {
+		if err := handler.moveByExpression(strings.Join(authors, ", "), initialItems, move[0], move[1]); err != nil {
+			metrics.ParseFailures.WithLabelValues(handler.channel.Name).Inc()
+			log.Warn().Err(err).Msgf("Could not move `%s` from list `%s` to `%s`", move[0], handler.channel.Name, move[1])
+		}
+	}
+
+	recipes, content := splitRecipes(content)
+
+	items := initialItems
+	update := UpdateItems
+	if handler.channel.HasBehaviour(ShoppingBehaviour) {
+		update = UpdateShoppingItems
+	}
+	changedItems := update(items, content)
+	for _, request := range recipes {
+		if changedItems, err = handler.addRecipe(changedItems, request); err != nil {
+			// keep lines like `recipe book` or a misspelled recipe on the list rather than dropping them silently
+			metrics.ParseFailures.WithLabelValues(handler.channel.Name).Inc()
+			log.Warn().Err(err).Msgf("Could not add recipe `%s` to list `%s`, adding it as item", request.name, handler.channel.Name)
+			changedItems = update(changedItems, request.line)
+		}
+	}
+	updatedItems, err := StoreItems(handler.pantryClient, handler.historyClient, items, changedItems, strings.Join(authors, ", "))
+	if err != nil {
+		log.Error().Err(err).Msgf("Could not store list `%s`", handler.channel.Name)
+		return
+	}
+	handler.recordChanges(items, updatedItems, strings.Join(authors, ", "))
+	handler.restock(initialItems, updatedItems)
+
+	if err := session.ChannelMessagesBulkDelete(handler.channel.ID, removableMessageIDs); err != nil {
+		log.Error().Err(err).Msg("Could not bulk delete channel messages")
+	}
+
+	PublishItems(updatedItems, session, handler.channel, PageIDs(pages))
+}
+
+func (handler *ListHandler) MessageComponentInteractionEvent(session model.ChatSession, interaction *discordgo.InteractionCreate) {
+	handler.serialize(func() {
+		handler.messageComponentInteractionEvent(session, interaction)
+	})
+}
+
+func (handler *ListHandler) messageComponentInteractionEvent(session model.ChatSession, interaction *discordgo.InteractionCreate) {
+	var response *discordgo.InteractionResponse
+
+	customID, argument, _ := strings.Cut(interaction.MessageComponentData().CustomID, ":")
+	switch customID {
+	case EditButton:
+		items, err := handler.pantryClient.GetItems()
+		if err != nil {
+			log.Error().Err(err).Msgf("Could not load list `%s`", handler.channel.Name)
+			return
+		}
+		response = &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseModal,
+			Data: &discordgo.InteractionResponseData{
+				CustomID: EditModal,
+				Title:    handler.channel.Title,
+				Components: []discordgo.MessageComponent{
+					discordgo.ActionsRow{
+						Components: []discordgo.MessageComponent{
+							discordgo.TextInput{
+								CustomID: EditModalInput,
+								Style:    discordgo.TextInputParagraph,
+								Value:    model.ToList(items),
+							},
+						},
+					},
+				},
+			},
+		}
+	case UndoButton, RedoButton:
+		move, direction := handler.historyClient.Undo, "undo"
+		if interaction.MessageComponentData().CustomID == RedoButton {
+			move, direction = handler.historyClient.Redo, "redo"
+		}
+		metrics.Undos.WithLabelValues(handler.channel.Name, direction).Inc()
+		items, err := RestoreRevision(handler.pantryClient, move)
+		if err != nil {
+			log.Error().Err(err).Msgf("Could not restore revision of list `%s`", handler.channel.Name)
+			return
+		}
+		handler.respondWithItems(session, interaction, items)
+		return
+	case HistoryButton:
+		response = CreateHistoryResponse(handler.historyClient)
+	case MoveButton:
+		response = CreateMoveTargetResponse(handler.moveTargets())
+	case MoveTargetSelect:
+		items, err := handler.pantryClient.GetItems()
+		if err != nil {
+			log.Error().Err(err).Msgf("Could not load list `%s`", handler.channel.Name)
+			return
+		}
+		response = CreateMoveItemsResponse(items, interaction.MessageComponentData().Values[0])
+	case MoveItemsSelect:
+		var ids []int
+		for _, value := range interaction.MessageComponentData().Values {
+			if id, err := strconv.Atoi(value); err == nil {
+				ids = append(ids, id)
+			}
+		}
+		handler.later(func() {
+			handler.moveSelectedItems(session, interaction, ids, argument)
+		})
+		return
+	case TickButton:
+		items, err := handler.pantryClient.GetItems()
+		if err != nil {
+			log.Error().Err(err).Msgf("Could not load list `%s`", handler.channel.Name)
+			return
+		}
+		response = CreateTickItemsResponse(items)
+	case TickItemsSelect:
+		var ids []int
+		for _, value := range interaction.MessageComponentData().Values {
+			if id, err := strconv.Atoi(value); err == nil {
+				ids = append(ids, id)
+			}
+		}
+		reply := fmt.Sprintf("Ticked %d items", len(ids))
+		if _, err := handler.changeItems(InteractionAuthor(interaction), func(items []model.PantryItem) ([]model.PantryItem, error) {
+			return TickItems(items, ids), nil
+		}); err != nil {
+			log.Error().Err(err).Msgf("Could not tick items of list `%s`", handler.channel.Name)
+			reply = fmt.Sprintf("Sorry, %s", err)
+		}
+		response = &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseUpdateMessage,
+			Data: &discordgo.InteractionResponseData{
+				Content:    reply,
+				Components: []discordgo.MessageComponent{},
+			},
+		}
+	case SuggestButton:
+		suggestions, err := handler.suggestions()
+		if err != nil {
+			log.Error().Err(err).Msgf("Could not suggest items for list `%s`", handler.channel.Name)
+			return
+		}
+		response = CreateSuggestionsResponse(suggestions, time.Now())
+	case SuggestItemsSelect:
+		chosen := interaction.MessageComponentData().Values
+		reply := describeSuggestions(chosen)
+		if suggestions, err := handler.suggestions(); err != nil {
+			log.Error().Err(err).Msgf("Could not suggest items for list `%s`", handler.channel.Name)
+			reply = fmt.Sprintf("Sorry, %s", err)
+		} else if _, err := handler.changeItems(InteractionAuthor(interaction), func(items []model.PantryItem) ([]model.PantryItem, error) {
+			return AddSuggestedItems(items, suggestions, chosen), nil
+		}); err != nil {
+			log.Error().Err(err).Msgf("Could not add suggested items to list `%s`", handler.channel.Name)
+			reply = fmt.Sprintf("Sorry, %s", err)
+		}
+		response = &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseUpdateMessage,
+			Data: &discordgo.InteractionResponseData{
+				Content:    reply,
+				Components: []discordgo.MessageComponent{},
+			},
+		}
+	case FinishTripButton:
+		reply := ""
+		if bought, err := handler.finishTrip(InteractionAuthor(interaction)); err != nil {
+			log.Error().Err(err).Msgf("Could not finish shopping trip of list `%s`", handler.channel.Name)
+			reply = fmt.Sprintf("Sorry, %s", err)
+		} else {
+			reply = describePurchases(bought)
+		}
+		response = CreateCommandResponse(reply)
+	default:
+		log.Error().Msgf("Could not map message component interaction event `%s`", interaction.MessageComponentData().CustomID)
+	}
+
+	_ = session.InteractionRespond(interaction.Interaction, response)
+}
+
+// moveSelectedItems moves the items chosen in the move select menu and reports the result on the menu.
+func (handler *ListHandler) moveSelectedItems(session model.ChatSession, interaction *discordgo.InteractionCreate, ids []int, targetName string) {
+	reply := ""
+	if target, ok := handler.findList(targetName); !ok {
+		reply = fmt.Sprintf("Sorry, there is no list `%s`", targetName)
+	} else if moved, err := handler.MoveItems(InteractionAuthor(interaction), ids, target); err != nil {
+		log.Error().Err(err).Msgf("Could not move items from list `%s` to `%s`", handler.channel.Name, targetName)
+		reply = fmt.Sprintf("Sorry, %s", err)
+	} else {
+		reply = describeMove(moved, targetName)
+	}
+	_ = session.InteractionRespond(interaction.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content:    reply,
+			Components: []discordgo.MessageComponent{},
+		},
+	})
+}
+
+func (handler *ListHandler) ModalSubmitInteractionEvent(session model.ChatSession, interaction *discordgo.InteractionCreate) {
+	handler.serialize(func() {
+		handler.modalSubmitInteractionEvent(session, interaction)
+	})
+}
+
+func (handler *ListHandler) modalSubmitInteractionEvent(session model.ChatSession, interaction *discordgo.InteractionCreate) {
+	var response *discordgo.InteractionResponse
+
+	switch interaction.ModalSubmitData().CustomID {
+	case EditModal:
+		items, err := handler.pantryClient.GetItems()
+		if err != nil {
+			log.Error().Err(err).Msgf("Could not load list `%s`", handler.channel.Name)
+			return
+		}
+		updatedItems, err := StoreItems(handler.pantryClient, handler.historyClient, items, UpdateItemsFromList(
+			items,
+			interaction.ModalSubmitData().Components[0].(*discordgo.ActionsRow).Components[0].(*discordgo.TextInput).Value,
+		), InteractionAuthor(interaction))
+		if err != nil {
+			log.Error().Err(err).Msgf("Could not store list `%s`", handler.channel.Name)
+			return
+		}
+		handler.recordCorrections(items, updatedItems, InteractionAuthor(interaction))
+		handler.respondWithItems(session, interaction, updatedItems)
+		handler.restock(items, updatedItems)
+		return
+	default:
+		log.Error().Msgf("Could not map modal-submit interaction event `%s`", interaction.ModalSubmitData().CustomID)
+	}
+
+	_ = session.InteractionRespond(interaction.Interaction, response)
+}
+
+func (handler *ListHandler) ApplicationCommandInteractionEvent(session model.ChatSession, interaction *discordgo.InteractionCreate) {
+	handler.serialize(func() {
+		handler.applicationCommandInteractionEvent(session, interaction)
+	})
+}
+
+func (handler *ListHandler) applicationCommandInteractionEvent(session model.ChatSession, interaction *discordgo.InteractionCreate) {
+	data := interaction.ApplicationCommandData()
+
+	if data.Name == ListCommand {
+		items, err := handler.pantryClient.GetItems()
+		if err != nil {
+			log.Error().Err(err).Msgf("Could not load list `%s`", handler.channel.Name)
+			_ = session.InteractionRespond(interaction.Interaction, CreateCommandResponse(fmt.Sprintf("Could not load list `%s`", handler.channel.Name)))
+			return
+		}
+		if option, ok := CommandOptions(data)[MineOption]; ok && option.BoolValue() {
+			if items = ItemsBy(items, InteractionAuthor(interaction)); len(items) == 0 {
+				_ = session.InteractionRespond(interaction.Interaction, CreateCommandResponse(fmt.Sprintf("You did not add any items to `%s`", handler.channel.Name)))
+				return
+			}
+		}
+		respondWithTable(session, interaction, ToMarkdownTable(items, handler.channel))
+		return
+	}
+	if data.Name == ScheduleCommand {
+		reply, err := ExecuteScheduleCommand(data, handler.scheduleClient, InteractionAuthor(interaction))
+		if err != nil {
+			reply = fmt.Sprintf("Sorry, %s", err)
+		}
+		respondWithTable(session, interaction, reply)
+		return
+	}
+	if data.Name == RecipeCommand {
+		reply, err := ExecuteRecipeCommand(data, handler.recipeClient, config.Config.Discord.Recipes, InteractionAuthor(interaction))
+		if err != nil {
+			reply = fmt.Sprintf("Sorry, %s", err)
+		}
+		_ = session.InteractionRespond(interaction.Interaction, CreateCommandResponse(reply))
+		return
+	}
+	if data.Name == StatsCommand {
+		reply := ""
+		if stats, err := CalculateStats(handler, handler.channel.Name); err != nil {
+			log.Error().Err(err).Msgf("Could not calculate stats of list `%s`", handler.channel.Name)
+			reply = fmt.Sprintf("Could not calculate stats of list `%s`", handler.channel.Name)
+		} else {
+			reply = model.ToStatsTable(stats)
+		}
+		_ = session.InteractionRespond(interaction.Interaction, CreateCommandResponse(reply))
+		return
+	}
+	if data.Name == ExportCommand {
+		handler.exportItems(session, interaction, data)
+		return
+	}
+	if data.Name == ImportCommand {
+		handler.importItems(session, interaction, data)
+		return
+	}
+
+	items, err := handler.pantryClient.GetItems()
+	if err != nil {
+		log.Error().Err(err).Msgf("Could not load list `%s`", handler.channel.Name)
+	}
+	updatedItems, reply, err := ExecuteCommand(data, handler.pantryClient, handler.historyClient, InteractionAuthor(interaction))
+	if err != nil {
+		_ = session.InteractionRespond(interaction.Interaction, CreateCommandResponse(fmt.Sprintf("Sorry, %s", err)))
+		return
+	}
+	_ = session.InteractionRespond(interaction.Interaction, CreateCommandResponse(reply))
+	RepublishItems(updatedItems, session, handler.channel)
+	if data.Name == UndoCommand {
+		metrics.Undos.WithLabelValues(handler.channel.Name, "undo").Inc()
+	} else if items != nil {
+		handler.recordChanges(items, updatedItems, InteractionAuthor(interaction))
+		handler.restock(items, updatedItems)
+	}
+}
+
+// exportItems responds with the list attached as file, only visible to the user who asked for it.
+func (handler *ListHandler) exportItems(session model.ChatSession, interaction *discordgo.InteractionCreate, data discordgo.ApplicationCommandInteractionData) {
+	format := CSVFormat
+	if option, ok := CommandOptions(data)[FormatOption]; ok {
+		format = option.StringValue()
+	}
+	items, err := handler.pantryClient.GetItems()
+	if err != nil {
+		log.Error().Err(err).Msgf("Could not load list `%s`", handler.channel.Name)
+		_ = session.InteractionRespond(interaction.Interaction, CreateCommandResponse(fmt.Sprintf("Could not load list `%s`", handler.channel.Name)))
+		return
+	}
+	export, err := ExportItems(items, format)
+	if err != nil {
+		_ = session.InteractionRespond(interaction.Interaction, CreateCommandResponse(fmt.Sprintf("Sorry, %s", err)))
+		return
+	}
+
+	response := CreateCommandResponse(fmt.Sprintf("Exported %d items of `%s`", len(items), handler.channel.Name))
+	response.Data.Files = []*discordgo.File{{
+		Name:        ExportFileName(handler.channel.Name, format),
+		ContentType: ExportContentType(format),
+		Reader:      bytes.NewReader(export),
+	}}
+	if err := session.InteractionRespond(interaction.Interaction, response); err != nil {
+		log.Error().Err(err).Msgf("Could not send export of list `%s`", handler.channel.Name)
+	}
+}
+
+// importItems merges the items of an attached file into the list or replaces the list with them. The format is
+// detected by the file name.
+func (handler *ListHandler) importItems(session model.ChatSession, interaction *discordgo.InteractionCreate, data discordgo.ApplicationCommandInteractionData) {
+	options := CommandOptions(data)
+	mode := MergeMode
+	if option, ok := options[ModeOption]; ok {
+		mode = option.StringValue()
+	}
+	var attachment *discordgo.MessageAttachment
+	if option, ok := options[FileOption]; ok && data.Resolved != nil {
+		id, _ := option.Value.(string) // StringValue only accepts string options
+		attachment = data.Resolved.Attachments[id]
+	}
+	if attachment == nil {
+		_ = session.InteractionRespond(interaction.Interaction, CreateCommandResponse("Sorry, please attach a file"))
+		return
+	}
+	format := FormatOf(attachment.Filename, attachment.ContentType)
+	if format == "" {
+		_ = session.InteractionRespond(interaction.Interaction, CreateCommandResponse(fmt.Sprintf("Sorry, `%s` is no CSV, JSON or text file", attachment.Filename)))
+		return
+	}
+
+	content, err := DownloadAttachment(attachment)
+	if err != nil {
+		log.Warn().Err(err).Msgf("Could not download `%s`", attachment.URL)
+		_ = session.InteractionRespond(interaction.Interaction, CreateCommandResponse(fmt.Sprintf("Sorry, could not download `%s`", attachment.Filename)))
+		return
+	}
+	imported, err := ImportItems(content, format)
+	if err != nil {
+		metrics.ParseFailures.WithLabelValues(handler.channel.Name).Inc()
+		_ = session.InteractionRespond(interaction.Interaction, CreateCommandResponse(fmt.Sprintf("Sorry, could not read `%s`: %s", attachment.Filename, err)))
+		return
+	}
+	if _, err := handler.changeItems(InteractionAuthor(interaction), func(items []model.PantryItem) ([]model.PantryItem, error) {
+		return ApplyImport(items, imported, mode)
+	}); err != nil {
+		log.Error().Err(err).Msgf("Could not import `%s` into list `%s`", attachment.Filename, handler.channel.Name)
+		_ = session.InteractionRespond(interaction.Interaction, CreateCommandResponse(fmt.Sprintf("Sorry, could not import `%s`", attachment.Filename)))
+		return
+	}
+
+	reply := fmt.Sprintf("Added %d items of `%s` to `%s`", len(imported), attachment.Filename, handler.channel.Name)
+	if mode == ReplaceMode {
+		reply = fmt.Sprintf("Replaced `%s` with %d items of `%s`", handler.channel.Name, len(imported), attachment.Filename)
+	}
+	_ = session.InteractionRespond(interaction.Interaction, CreateCommandResponse(reply))
+}
+
+func (handler *ListHandler) ApplicationCommandAutocompleteInteractionEvent(session model.ChatSession, interaction *discordgo.InteractionCreate) {
+	items, err := handler.pantryClient.GetItems()
+	if err != nil {
+		log.Error().Err(err).Msgf("Could not load list `%s`", handler.channel.Name)
+		return
+	}
+	_ = session.InteractionRespond(interaction.Interaction, CreateAutocompleteResponse(interaction.ApplicationCommandData(), items))
+}
+
+// respondWithItems acknowledges an interaction on the list message and republishes all pages of the list, as the
+// interaction response itself could only update the single message holding the buttons.
+func (handler *ListHandler) respondWithItems(session model.ChatSession, interaction *discordgo.InteractionCreate, items []model.PantryItem) {
+	if err := session.InteractionRespond(interaction.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
+	}); err != nil {
+		log.Error().Err(err).Msg("Could not acknowledge interaction")
+	}
+	RepublishItems(items, session, handler.channel)
+}
+
+// postExpiryDigest replaces the previous expiry digest of the channel with a current one.
+func (handler *ListHandler) postExpiryDigest() {
+	handler.serialize(handler.replaceExpiryDigest)
+}
+
+func (handler *ListHandler) replaceExpiryDigest() {
+	items, err := handler.pantryClient.GetItems()
+	if err != nil {
+		log.Error().Err(err).Msgf("Could not load list `%s`", handler.channel.Name)
+		return
+	}
+
+	digests, err := ExpiryDigests(handler.session, handler.channel.ID)
+	if err != nil {
+		log.Error().Err(err).Msgf("Could not find expiry digests of list `%s`", handler.channel.Name)
+		return
+	}
+	deleteMessages(handler.session, handler.channel.ID, PageIDs(digests))
+
+	digest := CreateExpiryDigest(items, handler.channel, config.Config.Discord.Categories, time.Now())
+	if digest == "" {
+		return
+	}
+	if _, err := handler.session.ChannelMessageSendComplex(handler.channel.ID, &discordgo.MessageSend{Content: digest}); err != nil {
+		log.Error().Err(err).Msg("Could not send expiry digest")
+	}
+}
@@ -0,0 +1,594 @@
+package service
+
+import (
+	"fmt"
+	"github.com/bwmarrin/discordgo"
+	"github.com/maribowman/roastbeef-swag/app/config"
+	"github.com/maribowman/roastbeef-swag/app/model"
+	"github.com/maribowman/roastbeef-swag/app/repository"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeListRegistry map[string]model.ListHandler
+
+func (registry fakeListRegistry) GetListHandlers() map[string]model.ListHandler {
+	return registry
+}
+
+// testListChannel configures a list channel with the defaults applied when loading the config.
+func testListChannel(name, id string) config.Channel {
+	return config.Channel{Name: name, ID: id, Type: config.ListChannelType, Table: name, LineBreak: 100}
+}
+
+// newTestListHandlers creates ready handlers for list channels on a temporary database and an in-memory Discord.
+func newTestListHandlers(t *testing.T, channels ...config.Channel) (*fakeChatSession, map[string]*ListHandler) {
+	previous := config.Config
+	t.Cleanup(func() { config.Config = previous })
+	config.Config.Discord.BotID = fakeBotID
+	config.Config.Discord.Channels = channels
+	config.Config.Database.Sqlite = filepath.Join(t.TempDir(), "pantry.db")
+	databaseClient := repository.NewDatabaseClient()
+	t.Cleanup(databaseClient.CloseDatabaseConnection)
+
+	session := newFakeChatSession()
+	registry := fakeListRegistry{}
+	handlers := map[string]*ListHandler{}
+	for _, channel := range channels {
+		handler := NewListHandler(channel, databaseClient, registry).(*ListHandler)
+		registry[channel.Name] = handler
+		handlers[channel.Name] = handler
+	}
+	for _, handler := range handlers {
+		handler.ReadyEvent(session, &discordgo.Ready{})
+	}
+	return session, handlers
+}
+
+// itemNames returns the names of the items in their order.
+func itemNames(items []model.PantryItem) []string {
+	var names []string
+	for _, item := range items {
+		names = append(names, item.Item)
+	}
+	return names
+}
+
+func TestListHandlerAddsItems(t *testing.T) {
+	// given
+	session, handlers := newTestListHandlers(t, testListChannel("groceries", "1"))
+	groceries := handlers["groceries"]
+
+	// when
+	groceries.MessageEvent(session, session.post("1", "mari", "2 milk\neggs"))
+
+	// then
+	items, err := groceries.GetItems()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"milk", "eggs"}, itemNames(items))
+	assert.Equal(t, 2.0, items[0].Amount)
+	assert.Equal(t, "mari", items[0].Author)
+	assert.Equal(t, []string{"milk", "eggs"}, itemNames(session.publishedItems("1")))
+	assert.Len(t, session.channelMessages("1"), 1, "the user message is deleted")
+}
+
+func TestListHandlerRemovesItems(t *testing.T) {
+	// given
+	session, handlers := newTestListHandlers(t, testListChannel("groceries", "1"))
+	groceries := handlers["groceries"]
+	groceries.MessageEvent(session, session.post("1", "mari", "milk\neggs\nbread"))
+
+	// when
+	groceries.MessageEvent(session, session.post("1", "mari", "2"))
+
+	// then
+	items, err := groceries.GetItems()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"milk", "bread"}, itemNames(items))
+	published := session.publishedItems("1")
+	assert.Equal(t, []string{"milk", "bread"}, itemNames(published))
+	assert.Equal(t, 2, published[1].Number)
+	removals, err := groceries.GetRemovals(time.Time{})
+	assert.NoError(t, err)
+	if assert.Len(t, removals, 1) {
+		assert.Equal(t, "eggs", removals[0].Item)
+	}
+}
+
+func TestListHandlerEditModal(t *testing.T) {
+	// given
+	session, handlers := newTestListHandlers(t, testListChannel("groceries", "1"))
+	groceries := handlers["groceries"]
+	groceries.MessageEvent(session, session.post("1", "mari", "milk\neggs"))
+
+	// when
+	groceries.MessageComponentInteractionEvent(session, componentInteraction("1", "mari", EditButton))
+
+	// then
+	modal := session.lastResponse()
+	assert.Equal(t, discordgo.InteractionResponseModal, modal.Type)
+	assert.Equal(t, EditModal, modal.Data.CustomID)
+	value := modal.Data.Components[0].(discordgo.ActionsRow).Components[0].(discordgo.TextInput).Value
+	assert.Equal(t, "[1] 1 milk\n[2] 1 eggs", value)
+
+	// and when
+	edited := strings.Split(value, "\n")[1] + "\n2 butter"
+	groceries.ModalSubmitInteractionEvent(session, modalInteraction("1", "mari", EditModal, edited))
+
+	// then
+	assert.Equal(t, discordgo.InteractionResponseDeferredMessageUpdate, session.lastResponse().Type)
+	items, err := groceries.GetItems()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"eggs", "butter"}, itemNames(items))
+	assert.Equal(t, []string{"eggs", "butter"}, itemNames(session.publishedItems("1")))
+}
+
+func TestListHandlerUndo(t *testing.T) {
+	// given
+	session, handlers := newTestListHandlers(t, testListChannel("groceries", "1"))
+	groceries := handlers["groceries"]
+	groceries.MessageEvent(session, session.post("1", "mari", "milk"))
+	groceries.MessageEvent(session, session.post("1", "mari", "eggs"))
+
+	// when
+	groceries.MessageComponentInteractionEvent(session, componentInteraction("1", "mari", UndoButton))
+
+	// then
+	items, err := groceries.GetItems()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"milk"}, itemNames(items))
+	assert.Equal(t, []string{"milk"}, itemNames(session.publishedItems("1")))
+
+	// and when
+	groceries.MessageComponentInteractionEvent(session, componentInteraction("1", "mari", RedoButton))
+
+	// then
+	assert.Equal(t, []string{"milk", "eggs"}, itemNames(session.publishedItems("1")))
+}
+
+func TestListHandlerPublishesLongLists(t *testing.T) {
+	// given
+	session, handlers := newTestListHandlers(t, testListChannel("groceries", "1"))
+	groceries := handlers["groceries"]
+	var lines []string
+	for index := range 150 {
+		lines = append(lines, fmt.Sprintf("long item name %c%c", 'a'+index/26, 'a'+index%26))
+	}
+
+	// when
+	for start := 0; start < len(lines); start += 50 {
+		session.post("1", "mari", strings.Join(lines[start:start+50], "\n"))
+	}
+	groceries.MessageEvent(session, session.post("1", "mari", "milk"))
+
+	// then
+	pages := session.pages("1")
+	assert.Greater(t, len(pages), 1)
+	for _, page := range pages {
+		assert.LessOrEqual(t, len(page), maxMessageLength)
+	}
+	assert.Len(t, session.publishedItems("1"), 151)
+	messages := session.channelMessages("1")
+	assert.Len(t, messages, len(pages), "all user messages are deleted")
+	for index, message := range messages {
+		assert.Equal(t, index == len(messages)-1, len(message.Components) != 0, "only the last page has buttons")
+	}
+
+	// and when
+	assert.Greater(t, len(pages), 2)
+	session.age("1", bulkDeleteMaxAge+time.Hour) // Discord deletes pages of a long-lived list only one by one
+	items, err := groceries.GetItems()
+	assert.NoError(t, err)
+	milk := items[slices.IndexFunc(items, func(item model.PantryItem) bool { return item.Item == "milk" })]
+	groceries.MessageEvent(session, session.post("1", "mari", fmt.Sprintf("* %d", milk.Number)))
+
+	// then
+	assert.Len(t, session.pages("1"), 1, "surplus pages are deleted")
+	assert.Equal(t, []string{"milk"}, itemNames(session.publishedItems("1")))
+	assert.NotEmpty(t, session.channelMessages("1")[0].Components)
+}
+
+func TestListHandlerKeepsExpiryDigestBelowPages(t *testing.T) {
+	// given
+	channel := testListChannel("tk", "1")
+	channel.Expiry = config.Expiry{Digest: "08:00", WarnDays: 7}
+	session, handlers := newTestListHandlers(t, channel)
+	tk := handlers["tk"]
+	tk.MessageEvent(session, session.post("1", "mari", "salmon exp 1.1.20"))
+	tk.postExpiryDigest()
+	var lines []string
+	for index := range 150 {
+		lines = append(lines, fmt.Sprintf("long item name %c%c", 'a'+index/26, 'a'+index%26))
+	}
+
+	// when
+	tk.MessageEvent(session, session.post("1", "mari", strings.Join(lines, "\n")))
+
+	// then
+	pages := session.pages("1")
+	assert.Greater(t, len(pages), 1)
+	messages := session.channelMessages("1")
+	assert.Len(t, messages, len(pages)+1)
+	for _, message := range messages[:len(pages)] {
+		assert.True(t, strings.HasPrefix(message.Content, "```"), "pages are consecutive")
+	}
+	assert.True(t, strings.HasPrefix(messages[len(messages)-1].Content, "**Expiry digest for tk**"), "digest is moved below new pages")
+	assert.Len(t, session.publishedItems("1"), 151)
+
+	// and when
+	tk.postExpiryDigest()
+
+	// then
+	messages = session.channelMessages("1")
+	assert.Len(t, messages, len(pages)+1, "previous digest is replaced")
+	assert.Contains(t, messages[len(messages)-1].Content, "salmon expired on 01.01.20")
+}
+
+func TestListHandlerConcurrentMessages(t *testing.T) {
+	// given
+	session, handlers := newTestListHandlers(t, testListChannel("groceries", "1"))
+	groceries := handlers["groceries"]
+	var wait sync.WaitGroup
+
+	// when
+	for index := range 50 {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			message := session.post("1", "mari", fmt.Sprintf("item%d", index))
+			groceries.MessageEvent(session, message)
+		}()
+	}
+	wait.Wait()
+
+	// then
+	items, err := groceries.GetItems()
+	assert.NoError(t, err)
+	assert.Len(t, items, 50)
+	var names []string
+	for _, item := range items {
+		names = append(names, item.Item)
+	}
+	slices.Sort(names)
+	assert.Len(t, slices.Compact(names), 50)
+	assert.Len(t, session.publishedItems("1"), 50)
+	for _, message := range session.channelMessages("1") {
+		assert.Equal(t, "bot", message.Author.ID, "user message %s was not processed", message.Content)
+	}
+}
+
+func TestListHandlerConcurrentChanges(t *testing.T) {
+	// given
+	session, handlers := newTestListHandlers(t, testListChannel("groceries", "1"))
+	groceries := handlers["groceries"]
+	var wait sync.WaitGroup
+
+	// when
+	for index := range 50 {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			if index%5 == 0 {
+				groceries.MessageEvent(session, session.post("1", "mari", "milk"))
+				return
+			}
+			_, err := groceries.ChangeItems("api", func(items []model.PantryItem) ([]model.PantryItem, error) {
+				return MergeItem(items, model.PantryItem{Item: "eggs", Amount: 1}), nil
+			})
+			assert.NoError(t, err)
+		}()
+	}
+	wait.Wait()
+
+	// then
+	items, err := groceries.GetItems()
+	assert.NoError(t, err)
+	eggs := slices.IndexFunc(items, func(item model.PantryItem) bool { return item.Item == "eggs" })
+	if assert.NotEqual(t, -1, eggs) {
+		assert.Equal(t, 40.0, items[eggs].Amount)
+	}
+	published := session.publishedItems("1")
+	assert.Len(t, published, len(items))
+	assert.True(t, slices.ContainsFunc(published, func(item model.PantryItem) bool { return item.Item == "eggs" && item.Amount == 40 }))
+}
+
+func TestListHandlersConcurrentMoves(t *testing.T) {
+	// given
+	session, handlers := newTestListHandlers(t,
+		testListChannel("groceries", "1"),
+		testListChannel("tk", "2"),
+	)
+	groceries, tk := handlers["groceries"], handlers["tk"]
+	tk.channel.Restock = config.Restock{List: "groceries", Rules: []config.RestockRule{{Item: "pizza", Minimum: 1, Amount: 1}}}
+	var seed []model.PantryItem
+	for index := range 20 {
+		seed = append(seed, model.PantryItem{Item: fmt.Sprintf("item%d", index), Amount: 1})
+	}
+	_, err := groceries.ChangeItems("mari", func([]model.PantryItem) ([]model.PantryItem, error) { return seed, nil })
+	assert.NoError(t, err)
+	var wait sync.WaitGroup
+
+	// when
+	for _, item := range seed {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			for round := range 4 {
+				source, target := groceries, tk
+				if round%2 == 1 {
+					source, target = tk, groceries
+				}
+				items, err := source.GetItems()
+				assert.NoError(t, err)
+				index := slices.IndexFunc(items, func(existing model.PantryItem) bool { return existing.Item == item.Item })
+				if !assert.NotEqual(t, -1, index, "%s is missing in %s", item.Item, source.channel.Name) {
+					return
+				}
+				_, err = source.MoveItems("mari", []int{items[index].ID}, target)
+				assert.NoError(t, err)
+			}
+		}()
+	}
+	for range 5 { // the moves race with restocks from tk to groceries
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			tk.MessageEvent(session, session.post("2", "mari", "pizza"))
+			_, err := tk.ChangeItems("mari", func(items []model.PantryItem) ([]model.PantryItem, error) {
+				return slices.DeleteFunc(slices.Clone(items), func(item model.PantryItem) bool { return item.Item == "pizza" }), nil
+			})
+			assert.NoError(t, err)
+		}()
+	}
+	done := make(chan struct{})
+	go func() {
+		wait.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(30 * time.Second):
+		t.Fatal("lists are deadlocked")
+	}
+
+	// then
+	groceryItems, err := groceries.GetItems()
+	assert.NoError(t, err)
+	tkItems, err := tk.GetItems()
+	assert.NoError(t, err)
+	for _, item := range seed {
+		assert.True(t, slices.ContainsFunc(groceryItems, func(existing model.PantryItem) bool { return existing.Item == item.Item }), item.Item)
+	}
+	assert.False(t, slices.ContainsFunc(tkItems, func(existing model.PantryItem) bool { return strings.HasPrefix(existing.Item, "item") }))
+	assert.Len(t, session.publishedItems("1"), len(groceryItems))
+	assert.Len(t, session.publishedItems("2"), len(tkItems))
+}
+
+// metricValue reads a counter or gauge, or the number of observations of a histogram, from the default registry.
+func metricValue(t *testing.T, name string, labels map[string]string) float64 {
+	families, err := prometheus.DefaultGatherer.Gather()
+	assert.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+	metrics:
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if value, ok := labels[label.GetName()]; ok && value != label.GetValue() {
+					continue metrics
+				}
+			}
+			switch {
+			case metric.GetCounter() != nil:
+				return metric.GetCounter().GetValue()
+			case metric.GetGauge() != nil:
+				return metric.GetGauge().GetValue()
+			case metric.GetHistogram() != nil:
+				return float64(metric.GetHistogram().GetSampleCount())
+			}
+		}
+	}
+	return 0
+}
+
+func TestListHandlerMovesTickedItems(t *testing.T) {
+	// given
+	session, handlers := newTestListHandlers(t, testListChannel("groceries", "1"), testListChannel("tk", "2"))
+	groceries, tk := handlers["groceries"], handlers["tk"]
+	items, err := groceries.ChangeItems("mari", func([]model.PantryItem) ([]model.PantryItem, error) {
+		return []model.PantryItem{
+			{Item: "pizza", Amount: 2, Checked: true, Note: RestockNote},
+			{Item: "milk", Amount: 1},
+		}, nil
+	})
+	assert.NoError(t, err)
+
+	// when
+	_, err = groceries.MoveItems("mari", []int{items[0].ID}, tk)
+
+	// then
+	assert.NoError(t, err)
+	moved, err := tk.GetItems()
+	assert.NoError(t, err)
+	assert.Len(t, moved, 1)
+	assert.Equal(t, "pizza", moved[0].Item)
+	assert.Equal(t, 2.0, moved[0].Amount)
+	assert.False(t, moved[0].Checked, "nothing could clear the tick on a list without shopping behaviour")
+	assert.Empty(t, moved[0].Note)
+	assert.Equal(t, []string{"pizza"}, itemNames(session.publishedItems("2")))
+}
+
+func TestListHandlerCountsMovesOutOfShoppingListsAsPurchases(t *testing.T) {
+	// given
+	groceriesChannel := testListChannel("groceries", "1")
+	groceriesChannel.Behaviours = []string{ShoppingBehaviour}
+	_, handlers := newTestListHandlers(t, groceriesChannel, testListChannel("tk", "2"))
+	groceries, tk := handlers["groceries"], handlers["tk"]
+	items, err := groceries.ChangeItems("mari", func([]model.PantryItem) ([]model.PantryItem, error) {
+		return []model.PantryItem{{Item: "pizza", Amount: 2}, {Item: "peas", Amount: 1, Checked: true}}, nil
+	})
+	assert.NoError(t, err)
+
+	// when
+	_, err = groceries.MoveItems("mari", []int{items[0].ID, items[1].ID}, tk)
+
+	// then
+	assert.NoError(t, err)
+	removals, err := groceries.GetRemovals(time.Time{})
+	assert.NoError(t, err)
+	assert.Len(t, removals, 2)
+	for _, removal := range removals {
+		assert.Equal(t, model.PurchasedReason, removal.Reason, "bought and frozen")
+	}
+	stats, err := CalculateStats(groceries, "groceries")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, stats.Consumed)
+
+	// and when
+	tkItems, err := tk.GetItems()
+	assert.NoError(t, err)
+	_, err = tk.MoveItems("mari", []int{tkItems[0].ID}, groceries)
+
+	// then
+	assert.NoError(t, err)
+	removals, err = tk.GetRemovals(time.Time{})
+	assert.NoError(t, err)
+	assert.Len(t, removals, 1)
+	assert.Equal(t, model.MovedReason, removals[0].Reason, "only moves out of shopping lists are purchases")
+}
+
+func TestListHandlerStatsSkipCorrections(t *testing.T) {
+	// given
+	session, handlers := newTestListHandlers(t, testListChannel("groceries", "1"))
+	groceries := handlers["groceries"]
+	groceries.MessageEvent(session, session.post("1", "mari", "cofee"))
+
+	// when
+	groceries.MessageEvent(session, session.post("1", "mari", "1"))
+
+	// then
+	items, err := groceries.GetItems()
+	assert.NoError(t, err)
+	assert.Empty(t, items)
+	stats, err := CalculateStats(groceries, "groceries")
+	assert.NoError(t, err)
+	assert.Zero(t, stats.Consumed, "a deleted typo is no purchase")
+	assert.Empty(t, stats.TopItems)
+	assert.Equal(t, map[string]int{model.RemovedReason: 1}, stats.Reasons)
+}
+
+func TestListHandlerKeepsUnknownRecipes(t *testing.T) {
+	// given
+	session, handlers := newTestListHandlers(t, testListChannel("groceries", "1"))
+	groceries := handlers["groceries"]
+	config.Config.Discord.Recipes = map[string][]string{"lasagne": {"500g minced meat", "lasagne sheets"}}
+
+	// when
+	groceries.MessageEvent(session, session.post("1", "mari", "recipe lasagna\nrecipe book\nmilk\nrecipe lasagne"))
+
+	// then
+	items, err := groceries.GetItems()
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"milk", "minced meat", "lasagne sheets", "recipe lasagna", "recipe book"}, itemNames(items))
+	assert.Equal(t, itemNames(items), itemNames(session.publishedItems("1")))
+}
+
+func TestListHandlerMetrics(t *testing.T) {
+	// given
+	fake, handlers := newTestListHandlers(t, testListChannel("metered", "1"), testListChannel("meteredTk", "2"))
+	session := meteredSession{fake}
+	metered, tk := handlers["metered"], handlers["meteredTk"]
+	channel := map[string]string{"channel": "metered"}
+	fetches := metricValue(t, "roastbeef_discord_request_duration_seconds", map[string]string{"call": "ChannelMessages"})
+	queries := metricValue(t, "roastbeef_sqlite_query_duration_seconds", map[string]string{"store": "pantry", "operation": "replace_items"})
+
+	// when
+	metered.MessageEvent(session, fake.post("1", "mari", "milk\neggs\nbread"))
+	metered.MessageEvent(session, fake.post("1", "mari", "2\n> 1 meteredTk\n> 9 meteredTk\nrecipe unknown"))
+	metered.MessageComponentInteractionEvent(session, componentInteraction("1", "mari", UndoButton))
+
+	// then
+	assert.Equal(t, 4.0, metricValue(t, "roastbeef_items_added_total", channel), "unknown recipe added as item")
+	assert.Equal(t, 2.0, metricValue(t, "roastbeef_items_removed_total", channel), "eggs removed, milk moved")
+	assert.Equal(t, 1.0, metricValue(t, "roastbeef_items_added_total", map[string]string{"channel": "meteredTk"}))
+	assert.Equal(t, 2.0, metricValue(t, "roastbeef_parse_failures_total", channel), "unknown item and recipe")
+	assert.Equal(t, 1.0, metricValue(t, "roastbeef_undo_total", map[string]string{"channel": "metered", "direction": "undo"}))
+	items, err := metered.GetItems()
+	assert.NoError(t, err)
+	assert.Equal(t, float64(len(items)), metricValue(t, "roastbeef_list_items", channel))
+	tkItems, err := tk.GetItems()
+	assert.NoError(t, err)
+	assert.Equal(t, float64(len(tkItems)), metricValue(t, "roastbeef_list_items", map[string]string{"channel": "meteredTk"}))
+	assert.Greater(t, metricValue(t, "roastbeef_discord_request_duration_seconds", map[string]string{"call": "ChannelMessages"}), fetches)
+	assert.Greater(t, metricValue(t, "roastbeef_sqlite_query_duration_seconds", map[string]string{"store": "pantry", "operation": "replace_items"}), queries)
+}
+
+func TestListHandlerExportAndImport(t *testing.T) {
+	// given
+	session, handlers := newTestListHandlers(t, testListChannel("groceries", "1"), testListChannel("tkGoods", "2"))
+	groceries, tkGoods := handlers["groceries"], handlers["tkGoods"]
+	groceries.MessageEvent(session, session.post("1", "mari", "2 pizza exp 05.2027\nice cream #dessert"))
+	tkGoods.MessageEvent(session, session.post("2", "lu", "peas\npizza"))
+
+	// when
+	groceries.ApplicationCommandInteractionEvent(session, commandInteraction("1", "mari", discordgo.ApplicationCommandInteractionData{
+		Name: ExportCommand,
+		Options: []*discordgo.ApplicationCommandInteractionDataOption{
+			{Name: FormatOption, Type: discordgo.ApplicationCommandOptionString, Value: JSONFormat},
+		},
+	}))
+
+	// then
+	response := session.lastResponse()
+	assert.Equal(t, "Exported 2 items of `groceries`", response.Data.Content)
+	assert.Len(t, response.Data.Files, 1)
+	assert.Equal(t, "groceries.json", response.Data.Files[0].Name)
+	export, err := io.ReadAll(response.Data.Files[0].Reader)
+	assert.NoError(t, err)
+
+	// and when
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		_, _ = writer.Write(export)
+	}))
+	t.Cleanup(server.Close)
+	importCommand := func(mode string) *discordgo.InteractionCreate {
+		return commandInteraction("2", "lu", discordgo.ApplicationCommandInteractionData{
+			Name: ImportCommand,
+			Options: []*discordgo.ApplicationCommandInteractionDataOption{
+				{Name: FileOption, Type: discordgo.ApplicationCommandOptionAttachment, Value: "42"},
+				{Name: ModeOption, Type: discordgo.ApplicationCommandOptionString, Value: mode},
+			},
+			Resolved: &discordgo.ApplicationCommandInteractionDataResolved{Attachments: map[string]*discordgo.MessageAttachment{
+				"42": {ID: "42", Filename: "groceries.json", URL: server.URL, Size: len(export)},
+			}},
+		})
+	}
+	tkGoods.ApplicationCommandInteractionEvent(session, importCommand(MergeMode))
+
+	// then
+	assert.Equal(t, "Added 2 items of `groceries.json` to `tkGoods`", session.lastResponse().Data.Content)
+	items, err := tkGoods.GetItems()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"[1] 1 peas", "[2] 3 pizza exp 31.05.27", "[3] 1 ice cream #dessert"}, listLines(items))
+	assert.Equal(t, "mari", items[2].Author, "exports keep who added an item")
+	assert.Equal(t, []string{"peas", "pizza", "ice cream"}, itemNames(session.publishedItems("2")))
+
+	// and when
+	tkGoods.ApplicationCommandInteractionEvent(session, importCommand(ReplaceMode))
+
+	// then
+	assert.Equal(t, "Replaced `tkGoods` with 2 items of `groceries.json`", session.lastResponse().Data.Content)
+	items, err = tkGoods.GetItems()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"[1] 2 pizza exp 31.05.27", "[2] 1 ice cream #dessert"}, listLines(items))
+}
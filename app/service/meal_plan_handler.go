@@ -0,0 +1,294 @@
+package service
+
+import (
+	"fmt"
+	"github.com/bwmarrin/discordgo"
+	"github.com/maribowman/roastbeef-swag/app/config"
+	"github.com/maribowman/roastbeef-swag/app/metrics"
+	"github.com/maribowman/roastbeef-swag/app/model"
+	"github.com/maribowman/roastbeef-swag/app/repository"
+	"github.com/rs/zerolog/log"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MealPlanHandler manages a meal plan channel, which shows the dishes of the current week from Monday to Sunday.
+// Dishes can reserve items of other lists, e.g. from the freezer, and push their missing ingredients onto the grocery
+// list configured as `groceries`. Events run on the actor of the channel, which may wait for the actors of lists, but
+// never the other way round.
+type MealPlanHandler struct {
+	actor        *actor
+	session      model.ChatSession
+	channel      config.Channel
+	mealClient   model.MealClient
+	recipeClient model.RecipeClient
+	lists        model.ListRegistry
+	weekSchedule sync.Once
+}
+
+func NewMealPlanHandler(channel config.Channel, databaseClient model.DatabaseClient, lists model.ListRegistry) model.BotHandler {
+	log.Debug().Msgf("Registering meal plan handler for `%s`", channel.Name)
+	return &MealPlanHandler{
+		actor:        newActor(),
+		channel:      channel,
+		mealClient:   repository.NewMealSqliteClient(databaseClient, channel.Table),
+		recipeClient: repository.NewRecipeSqliteClient(databaseClient),
+		lists:        lists,
+	}
+}
+
+// meals returns the meals of the current week and its monday.
+func (handler *MealPlanHandler) meals() ([]model.Meal, time.Time, error) {
+	monday := WeekStart(time.Now())
+	meals, err := handler.mealClient.GetMeals(monday, monday.AddDate(0, 0, 7))
+	return meals, monday, err
+}
+
+// planMeal assigns a dish to a day. The items reserved by a former dish of the day are released.
+func (handler *MealPlanHandler) planMeal(request mealRequest, meals []model.Meal, author string) error {
+	for _, meal := range meals {
+		if meal.Day.Equal(request.day) {
+			handler.release(meal, author)
+		}
+	}
+	if request.dish == clearMeal {
+		return handler.mealClient.RemoveMeal(request.day)
+	}
+
+	meal := model.Meal{Day: request.day, Dish: request.dish, Factor: request.factor, Author: author}
+	if request.list != "" {
+		list, ok := findList(handler.lists, request.list)
+		if !ok {
+			return fmt.Errorf("there is no list `%s`", request.list)
+		}
+		items, err := list.GetItems()
+		if err != nil {
+			return err
+		}
+		ids, err := SelectItemIDs(items, request.expression)
+		if err != nil {
+			return err
+		}
+		if _, err := list.ChangeItems(author, func(items []model.PantryItem) ([]model.PantryItem, error) {
+			return ReserveItems(items, ids, meal), nil
+		}); err != nil {
+			return err
+		}
+		for _, item := range items {
+			if slices.Contains(ids, item.ID) {
+				meal.Reserved = append(meal.Reserved, model.Reservation{List: request.list, ItemID: item.ID, Item: item.Item})
+			}
+		}
+	}
+	_, err := handler.mealClient.SaveMeal(meal)
+	return err
+}
+
+// release removes the reservation notes of a meal from the items of other lists.
+func (handler *MealPlanHandler) release(meal model.Meal, author string) {
+	ids := map[string][]int{}
+	for _, reservation := range meal.Reserved {
+		ids[reservation.List] = append(ids[reservation.List], reservation.ItemID)
+	}
+	for name, listIDs := range ids {
+		list, ok := findList(handler.lists, name)
+		if !ok {
+			continue
+		}
+		if _, err := list.ChangeItems(author, func(items []model.PantryItem) ([]model.PantryItem, error) {
+			return ReleaseItems(items, listIDs, meal), nil
+		}); err != nil {
+			log.Error().Err(err).Msgf("Could not release items of `%s` in list `%s`", meal.Dish, name)
+		}
+	}
+}
+
+// pushMeals adds the missing ingredients of the planned dishes to the grocery list. The stock lists are checked first.
+func (handler *MealPlanHandler) pushMeals(author string) ([]model.Meal, error) {
+	groceries, ok := findList(handler.lists, handler.channel.Groceries)
+	if !ok {
+		return nil, fmt.Errorf("there is no grocery list `%s`", handler.channel.Groceries)
+	}
+	meals, _, err := handler.meals()
+	if err != nil {
+		return nil, err
+	}
+	stored, err := handler.recipeClient.GetRecipes()
+	if err != nil {
+		return nil, err
+	}
+
+	var stock []model.PantryItem
+	reserved := map[int][]model.PantryItem{}
+	for _, name := range handler.channel.Stock {
+		list, ok := findList(handler.lists, name)
+		if !ok {
+			return nil, fmt.Errorf("there is no stock list `%s`", name)
+		}
+		items, err := list.GetItems()
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			if mealID, ok := handler.reservedBy(meals, list, item.ID); ok {
+				reserved[mealID] = append(reserved[mealID], item)
+			} else {
+				stock = append(stock, item)
+			}
+		}
+	}
+
+	var pushed []model.Meal
+	if _, err := groceries.ChangeItems(author, func(items []model.PantryItem) ([]model.PantryItem, error) {
+		now := time.Now()
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		items, pushed = PushMeals(items, meals, MergeRecipes(config.Config.Discord.Recipes, stored), stock, reserved, config.Config.Discord.Synonyms, today)
+		return items, nil
+	}); err != nil {
+		return nil, err
+	}
+	for _, meal := range pushed {
+		if _, err := handler.mealClient.SaveMeal(meal); err != nil {
+			log.Error().Err(err).Msgf("Could not mark `%s` as pushed", meal.Dish)
+		}
+	}
+	return pushed, nil
+}
+
+// reservedBy returns the ID of the meal an item of a list is reserved for.
+func (handler *MealPlanHandler) reservedBy(meals []model.Meal, list model.ListHandler, itemID int) (int, bool) {
+	for _, meal := range meals {
+		for _, reservation := range meal.Reserved {
+			if reservation.ItemID != itemID {
+				continue
+			}
+			if reservedList, ok := findList(handler.lists, reservation.List); ok && reservedList == list {
+				return meal.ID, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// republish publishes the plan of the current week, which also rolls the plan over to a new week.
+func (handler *MealPlanHandler) republish() {
+	if handler.session == nil {
+		return
+	}
+	pages, _, _, _, err := PreProcessMessageEvent(handler.session, handler.channel.ID)
+	if err != nil {
+		log.Error().Err(err).Msgf("Could not find bot messages of channel %s", handler.channel.ID)
+		return
+	}
+	handler.publish(PageIDs(pages))
+}
+
+func (handler *MealPlanHandler) publish(pageIDs []string) {
+	meals, monday, err := handler.meals()
+	if err != nil {
+		log.Error().Err(err).Msgf("Could not load meal plan `%s`", handler.channel.Name)
+		return
+	}
+	table := model.ToMealPlanTable(meals, monday, handler.channel.DateFormat)
+	components := CreateMealPlanButtons(handler.channel.Groceries)
+
+	if len(pageIDs) == 0 {
+		if _, err := handler.session.ChannelMessageSendComplex(handler.channel.ID, &discordgo.MessageSend{
+			Content:    table,
+			Components: components,
+		}); err != nil {
+			log.Error().Err(err).Msg("Could not send complex message")
+		}
+		return
+	}
+	editedMessage := discordgo.NewMessageEdit(handler.channel.ID, pageIDs[0])
+	editedMessage.SetContent(table)
+	editedMessage.Components = &components
+	if _, err := handler.session.ChannelMessageEditComplex(editedMessage); err != nil {
+		log.Error().Err(err).Msgf("Could not edit message %s", pageIDs[0])
+	}
+	if len(pageIDs) > 1 {
+		deleteMessages(handler.session, handler.channel.ID, pageIDs[1:])
+	}
+}
+
+func (handler *MealPlanHandler) ReadyEvent(session model.ChatSession, ready *discordgo.Ready) {
+	handler.actor.do(func() {
+		handler.session = session
+		handler.republish()
+	})
+	handler.weekSchedule.Do(func() { // ready fires again on every reconnect
+		if err := scheduleDaily("00:00", func() { handler.actor.do(handler.republish) }); err != nil {
+			log.Error().Err(err).Msgf("Could not schedule roll over of meal plan `%s`", handler.channel.Name)
+		}
+	})
+	log.Debug().Msgf("Initialized meal plan handler for `%s`", handler.channel.Name)
+}
+
+func (handler *MealPlanHandler) MessageEvent(session model.ChatSession, message *discordgo.MessageCreate) {
+	handler.actor.do(func() {
+		handler.messageEvent(session, message)
+	})
+}
+
+func (handler *MealPlanHandler) messageEvent(session model.ChatSession, message *discordgo.MessageCreate) {
+	pages, content, authors, removableMessageIDs, err := PreProcessMessageEvent(session, handler.channel.ID)
+	if err != nil {
+		log.Error().Err(err).Msg("Error while processing message event")
+		return
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		meals, monday, err := handler.meals()
+		if err != nil {
+			log.Error().Err(err).Msgf("Could not load meal plan `%s`", handler.channel.Name)
+			return
+		}
+		request, err := ParseMeal(line, monday)
+		if err == nil {
+			err = handler.planMeal(request, meals, strings.Join(authors, ", "))
+		}
+		if err != nil {
+			metrics.ParseFailures.WithLabelValues(handler.channel.Name).Inc()
+			log.Warn().Err(err).Msgf("Could not plan `%s` in meal plan `%s`", line, handler.channel.Name)
+		}
+	}
+
+	if err := session.ChannelMessagesBulkDelete(handler.channel.ID, removableMessageIDs); err != nil {
+		log.Error().Err(err).Msg("Could not bulk delete channel messages")
+	}
+	handler.publish(PageIDs(pages))
+}
+
+func (handler *MealPlanHandler) MessageComponentInteractionEvent(session model.ChatSession, interaction *discordgo.InteractionCreate) {
+	if interaction.MessageComponentData().CustomID != PushMealsButton {
+		return
+	}
+	handler.actor.do(func() {
+		reply := ""
+		if pushed, err := handler.pushMeals(InteractionAuthor(interaction)); err != nil {
+			log.Error().Err(err).Msgf("Could not push meal plan `%s`", handler.channel.Name)
+			reply = fmt.Sprintf("Sorry, %s", err)
+		} else {
+			reply = describePushedMeals(pushed, handler.channel.Groceries)
+		}
+		_ = session.InteractionRespond(interaction.Interaction, CreateCommandResponse(reply))
+		handler.republish()
+	})
+}
+
+func (handler *MealPlanHandler) ModalSubmitInteractionEvent(model.ChatSession, *discordgo.InteractionCreate) {
+}
+
+func (handler *MealPlanHandler) ApplicationCommandInteractionEvent(session model.ChatSession, interaction *discordgo.InteractionCreate) {
+	_ = session.InteractionRespond(interaction.Interaction, CreateCommandResponse(fmt.Sprintf("Please choose a %s", ListOption)))
+}
+
+func (handler *MealPlanHandler) ApplicationCommandAutocompleteInteractionEvent(session model.ChatSession, interaction *discordgo.InteractionCreate) {
+	_ = session.InteractionRespond(interaction.Interaction, CreateAutocompleteResponse(interaction.ApplicationCommandData(), nil))
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"fmt"
+	"github.com/bwmarrin/discordgo"
+	"github.com/maribowman/roastbeef-swag/app/model"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	PushMealsButton = "push-meals-button"
+	clearMeal       = "-"
+)
+
+// mealRegex matches lines like `tue lasagne x2` or `wed salmon @tk 3 5`. CAPTURE GROUPS: 1 weekday, 2 dish,
+// 3 factor, 4 list and 5 numbers of reserved items.
+var mealRegex = regexp.MustCompile(`(?i)^(\w+)\s+(.+?)(?:\s+x\s*(\d+(?:[.,]\d+)?))?(?:\s+@(\w+)\s+([\d\s*-]+))?$`)
+
+// mealRequest is a line of a meal plan channel assigning a dish to a day.
+type mealRequest struct {
+	day        time.Time
+	dish       string // `-` clears the day
+	factor     float64
+	list       string // list of reserved items, empty if nothing is reserved
+	expression string // numbers of the reserved items like `3 5-7`
+}
+
+// WeekStart returns midnight of the monday of the week of the given time.
+func WeekStart(now time.Time) time.Time {
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+}
+
+// ParseMeal parses a line like `tue lasagne x2` or `wed salmon @tk 3` into a dish for a day of the week starting on
+// monday. Weekdays may be abbreviated as long as they are unique, e.g. `mo`, `tue` or `thursday`.
+func ParseMeal(line string, monday time.Time) (mealRequest, error) {
+	match := mealRegex.FindStringSubmatch(strings.TrimSpace(line))
+	if match == nil {
+		return mealRequest{}, fmt.Errorf("`%s` is not like `tue lasagne x2` or `wed salmon @tk 3`", line)
+	}
+	weekday, err := parseWeekday(match[1])
+	if err != nil {
+		return mealRequest{}, err
+	}
+	factor := 1.0
+	if match[3] != "" {
+		factor, _ = strconv.ParseFloat(strings.ReplaceAll(match[3], ",", "."), 64)
+	}
+	if factor <= 0 {
+		return mealRequest{}, fmt.Errorf("invalid factor `%s`", match[3])
+	}
+	return mealRequest{
+		day:        monday.AddDate(0, 0, (int(weekday)+6)%7),
+		dish:       strings.TrimSpace(match[2]),
+		factor:     factor,
+		list:       match[4],
+		expression: strings.TrimSpace(match[5]),
+	}, nil
+}
+
+func parseWeekday(value string) (time.Weekday, error) {
+	value = strings.ToLower(value)
+	var matches []time.Weekday
+	for name, weekday := range weekdays {
+		if len(value) >= 2 && strings.HasPrefix(name, value) {
+			matches = append(matches, weekday)
+		}
+	}
+	if len(matches) != 1 {
+		return 0, fmt.Errorf("`%s` is no weekday", value)
+	}
+	return matches[0], nil
+}
+
+// reservationNote marks items of other lists which are reserved for a meal, e.g. `for Tue lasagne`.
+func reservationNote(meal model.Meal) string {
+	return fmt.Sprintf("for %s %s", meal.Day.Format("Mon"), meal.Dish)
+}
+
+// ReserveItems notes the meal on the items with the given IDs.
+func ReserveItems(items []model.PantryItem, ids []int, meal model.Meal) []model.PantryItem {
+	reservedItems := slices.Clone(items) // keep the original list intact for the history
+	for index := range reservedItems {
+		if slices.Contains(ids, reservedItems[index].ID) {
+			reservedItems[index].Note = reservationNote(meal)
+		}
+	}
+	return reservedItems
+}
+
+// ReleaseItems removes the note of a meal from its reserved items. Notes changed in the meantime are kept.
+func ReleaseItems(items []model.PantryItem, ids []int, meal model.Meal) []model.PantryItem {
+	releasedItems := slices.Clone(items)
+	for index := range releasedItems {
+		if slices.Contains(ids, releasedItems[index].ID) && releasedItems[index].Note == reservationNote(meal) {
+			releasedItems[index].Note = ""
+		}
+	}
+	return releasedItems
+}
+
+// PushMeals adds the missing ingredients of all meals from today on, which have a recipe and were not pushed before,
+// to the grocery list. Every meal may use the items reserved for it and the shared stock, but not the items reserved
+// for other meals. The pushed meals are returned.
+func PushMeals(groceries []model.PantryItem, meals []model.Meal, recipes []model.Recipe, stock []model.PantryItem, reserved map[int][]model.PantryItem, synonyms map[string]string, today time.Time) ([]model.PantryItem, []model.Meal) {
+	stock = slices.Clone(stock)
+	var pushed []model.Meal
+	for _, meal := range meals {
+		if meal.Pushed || meal.Day.Before(today) {
+			continue
+		}
+		recipe, ok := FindRecipe(recipes, meal.Dish)
+		if !ok {
+			continue
+		}
+		own := slices.Clone(reserved[meal.ID])
+		mealStock := append(own, stock...)
+		groceries = addRecipe(groceries, recipe, meal.Factor, mealStock, synonyms)
+		copy(stock, mealStock[len(own):])
+
+		meal.Pushed = true
+		pushed = append(pushed, meal)
+	}
+	return groceries, pushed
+}
+
+// describePushedMeals confirms the meals whose ingredients were added to the grocery list.
+func describePushedMeals(pushed []model.Meal, groceries string) string {
+	if len(pushed) == 0 {
+		return "There are no upcoming dishes with a recipe, which were not pushed yet"
+	}
+	var dishes []string
+	for _, meal := range pushed {
+		dishes = append(dishes, meal.Dish)
+	}
+	return fmt.Sprintf("Added the missing ingredients of %s to `%s`", strings.Join(dishes, ", "), groceries)
+}
+
+// CreateMealPlanButtons offers to push the missing ingredients if the channel has a grocery list.
+func CreateMealPlanButtons(groceries string) []discordgo.MessageComponent {
+	if groceries == "" {
+		return []discordgo.MessageComponent{}
+	}
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label: "Push to " + groceries,
+					Emoji: &discordgo.ComponentEmoji{
+						Name: "🛒",
+					},
+					Style:    discordgo.SecondaryButton,
+					CustomID: PushMealsButton,
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"github.com/maribowman/roastbeef-swag/app/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestWeekStart(t *testing.T) {
+	assert.Equal(t, time.Date(2026, 10, 12, 0, 0, 0, 0, time.Local), WeekStart(time.Date(2026, 10, 18, 21, 15, 0, 0, time.Local)))
+	assert.Equal(t, time.Date(2026, 10, 19, 0, 0, 0, 0, time.Local), WeekStart(time.Date(2026, 10, 19, 0, 0, 0, 0, time.Local)))
+}
+
+func TestParseMeal(t *testing.T) {
+	// given
+	monday := time.Date(2026, 10, 19, 0, 0, 0, 0, time.Local)
+	tests := map[string]struct {
+		line     string
+		expected mealRequest
+	}{
+		"dish": {
+			line:     "mon chili con carne",
+			expected: mealRequest{day: monday, dish: "chili con carne", factor: 1},
+		},
+		"factor": {
+			line:     "Tue lasagne x2",
+			expected: mealRequest{day: monday.AddDate(0, 0, 1), dish: "lasagne", factor: 2},
+		},
+		"reservation": {
+			line:     "thursday salmon x1,5 @tk 3 5-6",
+			expected: mealRequest{day: monday.AddDate(0, 0, 3), dish: "salmon", factor: 1.5, list: "tk", expression: "3 5-6"},
+		},
+		"clear": {
+			line:     "su -",
+			expected: mealRequest{day: monday.AddDate(0, 0, 6), dish: "-", factor: 1},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// when
+			actual, err := ParseMeal(test.line, monday)
+
+			// then
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+
+	// and
+	_, err := ParseMeal("t lasagne", monday)
+	assert.Error(t, err)
+	_, err = ParseMeal("lasagne", monday)
+	assert.Error(t, err)
+}
+
+func TestReserveAndReleaseItems(t *testing.T) {
+	// given
+	meal := model.Meal{Day: time.Date(2026, 10, 20, 0, 0, 0, 0, time.Local), Dish: "salmon"}
+	items := []model.PantryItem{{ID: 1, Item: "salmon"}, {ID: 2, Item: "peas"}, {ID: 3, Item: "pizza", Note: "auto-added"}}
+
+	// when
+	reserved := ReserveItems(items, []int{1, 2}, meal)
+
+	// then
+	assert.Equal(t, "for Tue salmon", reserved[0].Note)
+	assert.Equal(t, "for Tue salmon", reserved[1].Note)
+	assert.Equal(t, "auto-added", reserved[2].Note)
+	assert.Empty(t, items[0].Note)
+
+	// and
+	reserved[1].Note = "for the kids"
+	released := ReleaseItems(reserved, []int{1, 2, 3}, meal)
+	assert.Empty(t, released[0].Note)
+	assert.Equal(t, "for the kids", released[1].Note)
+	assert.Equal(t, "auto-added", released[2].Note)
+}
+
+func TestPushMeals(t *testing.T) {
+	// given
+	today := time.Date(2026, 10, 20, 0, 0, 0, 0, time.Local)
+	recipes := []model.Recipe{
+		{Name: "lasagne", Ingredients: []string{"500g minced meat", "2 onions"}},
+		{Name: "salmon", Ingredients: []string{"2 salmon", "400g peas"}},
+	}
+	meals := []model.Meal{
+		{ID: 1, Day: today.AddDate(0, 0, -1), Dish: "lasagne", Factor: 1},
+		{ID: 2, Day: today, Dish: "lasagne", Factor: 2},
+		{ID: 3, Day: today.AddDate(0, 0, 1), Dish: "salmon", Factor: 1},
+		{ID: 4, Day: today.AddDate(0, 0, 2), Dish: "pizza", Factor: 1},
+		{ID: 5, Day: today.AddDate(0, 0, 3), Dish: "lasagne", Factor: 1, Pushed: true},
+		{ID: 6, Day: today.AddDate(0, 0, 4), Dish: "lasagne", Factor: 1},
+	}
+	stock := []model.PantryItem{{ID: 7, Item: "onions", Amount: 5}, {ID: 8, Item: "peas", Amount: 0.3, Unit: "kg"}}
+	reserved := map[int][]model.PantryItem{3: {{ID: 9, Item: "salmon", Amount: 2}}}
+
+	// when
+	groceries, pushed := PushMeals(nil, meals, recipes, stock, reserved, nil, today)
+
+	// then
+	assert.Equal(t, []int{2, 3, 6}, []int{pushed[0].ID, pushed[1].ID, pushed[2].ID})
+	assert.True(t, pushed[0].Pushed)
+	assert.Len(t, groceries, 3)
+	assert.Equal(t, "minced meat", groceries[0].Item)
+	assert.Equal(t, 1500.0, groceries[0].Amount) // 1000g for tuesday and 500g for saturday
+	assert.Equal(t, "peas", groceries[1].Item)
+	assert.Equal(t, 100.0, groceries[1].Amount)
+	assert.Equal(t, "onions", groceries[2].Item)
+	assert.Equal(t, 1.0, groceries[2].Amount) // 4 onions for tuesday and 1 of 2 for saturday are in stock
+
+	// and
+	assert.Equal(t, 5.0, stock[0].Amount, "stock must not change")
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/maribowman/roastbeef-swag/app/metrics"
+	"github.com/maribowman/roastbeef-swag/app/model"
+	"time"
+)
+
+// meteredSession records latency and errors of all Discord API calls of the handlers.
+type meteredSession struct {
+	session model.ChatSession
+}
+
+func (metered meteredSession) ChannelMessages(channelID string, limit int, beforeID, afterID, aroundID string, options ...discordgo.RequestOption) ([]*discordgo.Message, error) {
+	start := time.Now()
+	messages, err := metered.session.ChannelMessages(channelID, limit, beforeID, afterID, aroundID, options...)
+	metrics.ObserveDiscordCall("ChannelMessages", start, err)
+	return messages, err
+}
+
+func (metered meteredSession) ChannelMessageSendComplex(channelID string, data *discordgo.MessageSend, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	start := time.Now()
+	message, err := metered.session.ChannelMessageSendComplex(channelID, data, options...)
+	metrics.ObserveDiscordCall("ChannelMessageSendComplex", start, err)
+	return message, err
+}
+
+func (metered meteredSession) ChannelMessageEditComplex(data *discordgo.MessageEdit, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	start := time.Now()
+	message, err := metered.session.ChannelMessageEditComplex(data, options...)
+	metrics.ObserveDiscordCall("ChannelMessageEditComplex", start, err)
+	return message, err
+}
+
+func (metered meteredSession) ChannelMessageDelete(channelID, messageID string, options ...discordgo.RequestOption) error {
+	start := time.Now()
+	err := metered.session.ChannelMessageDelete(channelID, messageID, options...)
+	metrics.ObserveDiscordCall("ChannelMessageDelete", start, err)
+	return err
+}
+
+func (metered meteredSession) ChannelMessagesBulkDelete(channelID string, messageIDs []string, options ...discordgo.RequestOption) error {
+	start := time.Now()
+	err := metered.session.ChannelMessagesBulkDelete(channelID, messageIDs, options...)
+	metrics.ObserveDiscordCall("ChannelMessagesBulkDelete", start, err)
+	return err
+}
+
+func (metered meteredSession) InteractionRespond(interaction *discordgo.Interaction, response *discordgo.InteractionResponse, options ...discordgo.RequestOption) error {
+	start := time.Now()
+	err := metered.session.InteractionRespond(interaction, response, options...)
+	metrics.ObserveDiscordCall("InteractionRespond", start, err)
+	return err
+}
+
+func (metered meteredSession) FollowupMessageCreate(interaction *discordgo.Interaction, wait bool, data *discordgo.WebhookParams, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	start := time.Now()
+	message, err := metered.session.FollowupMessageCreate(interaction, wait, data, options...)
+	metrics.ObserveDiscordCall("FollowupMessageCreate", start, err)
+	return message, err
+}
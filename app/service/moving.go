@@ -0,0 +1,135 @@
+package service
+
+import (
+	"fmt"
+	"github.com/bwmarrin/discordgo"
+	"github.com/maribowman/roastbeef-swag/app/model"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const (
+	MoveButton       = "move-button"
+	MoveTargetSelect = "move-target-select"
+	MoveItemsSelect  = "move-items-select" // followed by `:<target list>`
+
+	maxSelectOptions = 25 // Discord limit
+)
+
+var moveRegex = regexp.MustCompile(`^>\s*(.+?)\s+(\S+)$`)
+
+// SelectItemIDs returns the IDs of all items matched by the same expression as used to remove items, e.g. `3 5-7`.
+func SelectItemIDs(items []model.PantryItem, expression string) ([]int, error) {
+	remaining, err := RemoveItems(items, expression)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []int
+	for _, item := range items {
+		if !slices.ContainsFunc(remaining, func(remainingItem model.PantryItem) bool {
+			return remainingItem.ID == item.ID
+		}) {
+			ids = append(ids, item.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("no items match `%s`", expression)
+	}
+	return ids, nil
+}
+
+// splitMoves separates lines like `> 3 5-7 tk` from the other content of a message. Moves are returned as pairs of
+// the item expression and the target list.
+func splitMoves(content string) ([][2]string, string) {
+	var moves [][2]string
+	var lines []string
+	for _, line := range strings.Split(content, "\n") {
+		if match := moveRegex.FindStringSubmatch(strings.TrimSpace(line)); match != nil {
+			moves = append(moves, [2]string{match[1], match[2]})
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return moves, strings.Join(lines, "\n")
+}
+
+// CreateMoveTargetResponse asks only the user who pressed the move button for the list to move items to.
+func CreateMoveTargetResponse(lists []string) *discordgo.InteractionResponse {
+	var options []discordgo.SelectMenuOption
+	for _, list := range lists {
+		options = append(options, discordgo.SelectMenuOption{Label: list, Value: list})
+	}
+	if len(options) == 0 {
+		return CreateCommandResponse("There is no other list to move items to")
+	}
+
+	return &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: "Where to move items?",
+			Flags:   discordgo.MessageFlagsEphemeral,
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.SelectMenu{
+							CustomID:    MoveTargetSelect,
+							Placeholder: "List",
+							Options:     options,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// CreateMoveItemsResponse replaces the list selection with a selection of the items to move to the target list.
+// Discord limits the selection to the first 25 items.
+func CreateMoveItemsResponse(items []model.PantryItem, target string) *discordgo.InteractionResponse {
+	var options []discordgo.SelectMenuOption
+	for _, item := range items[:min(len(items), maxSelectOptions)] {
+		options = append(options, discordgo.SelectMenuOption{
+			Label: fmt.Sprintf("%d - %s", item.Number, item.Item),
+			Value: fmt.Sprint(item.ID),
+		})
+	}
+	if len(options) == 0 {
+		return &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseUpdateMessage,
+			Data: &discordgo.InteractionResponseData{
+				Content:    "There are no items to move",
+				Components: []discordgo.MessageComponent{},
+			},
+		}
+	}
+
+	return &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content: fmt.Sprintf("Which items to move to `%s`?", target),
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.SelectMenu{
+							CustomID:    MoveItemsSelect + ":" + target,
+							Placeholder: "Items",
+							MaxValues:   len(options),
+							Options:     options,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// describeMove confirms moved items to the user.
+func describeMove(moved []model.PantryItem, target string) string {
+	var names []string
+	for _, item := range moved {
+		names = append(names, model.DescribeItem(item))
+	}
+	return fmt.Sprintf("Moved %s to `%s`", strings.Join(names, ", "), target)
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"github.com/maribowman/roastbeef-swag/app/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSplitMoves(t *testing.T) {
+	// when
+	moves, content := splitMoves("\neggs 3\n> 3 5-7 tk\n>1 tkGoods\n2")
+
+	// then
+	assert.Equal(t, [][2]string{{"3 5-7", "tk"}, {"1", "tkGoods"}}, moves)
+	assert.Equal(t, "\neggs 3\n2", content)
+}
+
+func TestSelectItemIDs(t *testing.T) {
+	// given
+	items := []model.PantryItem{
+		{ID: 11, Number: 1, Item: "eggs"},
+		{ID: 12, Number: 2, Item: "milk"},
+		{ID: 13, Number: 3, Item: "salmon"},
+		{ID: 14, Number: 4, Item: "peas"},
+	}
+
+	// when
+	ids, err := SelectItemIDs(items, "1 3-4")
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []int{11, 13, 14}, ids)
+
+	// and
+	_, err = SelectItemIDs(items, "9")
+	assert.Error(t, err)
+	_, err = SelectItemIDs(items, "salmon")
+	assert.Error(t, err)
+}
@@ -1,8 +1,10 @@
 package service
 
 import (
+	"fmt"
 	"github.com/bwmarrin/discordgo"
 	"github.com/maribowman/roastbeef-swag/app/config"
+	"github.com/maribowman/roastbeef-swag/app/metrics"
 	"github.com/maribowman/roastbeef-swag/app/model"
 	"github.com/rs/zerolog/log"
 	"regexp"
@@ -13,26 +15,38 @@ import (
 )
 
 const (
-	GroceriesChannel = "groceries"
-	TkGoodsChannel   = "tkGoods"
-
 	EditButton     = "edit-button"
 	UndoButton     = "undo-button"
+	RedoButton     = "redo-button"
+	HistoryButton  = "history-button"
 	EditModal      = "edit-modal"
 	EditModalInput = "edit-modal-input"
+
+	maxMessageLength     = 2000
+	maxButtonsPerRow     = 5
+	historyViewSize      = 15
+	historyActionLength  = 40
+	initialRevisionActor = "bot"
 )
 
 var (
-	NumberPrefixRegex = regexp.MustCompile(`^\[(\d+)]\s`)
-	removeRegex       = regexp.MustCompile(`^(\*)?(?:\s*\d+)*\s*(\d+-\d+)?$`)
-	leadingQuantity   = regexp.MustCompile(`^(\d+)\s.*`)
-	trailingQuantity  = regexp.MustCompile(`\s(\d+)$`)
+	NumberPrefixRegex  = regexp.MustCompile(`^\[(\d+)]\s`)
+	removeRegex        = regexp.MustCompile(`^(\*)?(?:\s*\d+)*\s*(\d+-\d+)?$`)
+	leadingQuantity    = regexp.MustCompile(`(?i)^(` + model.QuantityPattern + `)\s+(.+)$`)
+	trailingQuantity   = regexp.MustCompile(`(?i)^(.+?)\s+(` + model.QuantityPattern + `)$`)
+	leadingAdjustment  = regexp.MustCompile(`^([+-])\s*(\d.*)$`)
+	trailingAdjustment = regexp.MustCompile(`^(.+?)\s+([+-])\s*(\d.*)$`)
+	categoryTagRegex   = regexp.MustCompile(`(?:^|\s)#(\w+)`)
+	expiryRegex        = regexp.MustCompile(`(?i)\s(?:exp|bb)\s+(\d{1,2}\.\d{1,2}\.\d{2}(?:\d{2})?|\d{1,2}\.\d{4})$`)
 )
 
-func PreProcessMessageEvent(session *discordgo.Session, channelID, dateFormat string) (
-	items []model.PantryItem,
-	lastBotMessageID string,
+// PreProcessMessageEvent collects all pending user input of a channel and marks it for deletion. The pages of the
+// published list are returned oldest first and are only read to seed an empty database table, the list state itself
+// lives in the database.
+func PreProcessMessageEvent(session model.ChatSession, channelID string) (
+	pages []*discordgo.Message,
 	content string,
+	authors []string,
 	removableMessageIDs []string,
 	err error,
 ) {
@@ -42,30 +56,132 @@ func PreProcessMessageEvent(session *discordgo.Session, channelID, dateFormat st
 		return
 	}
 
-	var lastBotMessage *discordgo.Message
 	for _, msg := range channelMessages {
 		if msg.Author.ID == config.Config.Discord.BotID {
-			if lastBotMessage == nil {
-				lastBotMessage = msg
-				lastBotMessageID = msg.ID
-				items = model.FromMarkdownTable(msg.Content, dateFormat)
-				continue
-			} else if lastBotMessage.Timestamp.After(msg.Timestamp) {
-				removableMessageIDs = append(removableMessageIDs, lastBotMessageID) // remove previous bot msg
-				lastBotMessage = msg
-				lastBotMessageID = msg.ID
-				items = model.FromMarkdownTable(msg.Content, dateFormat)
-				// TODO read from file if present
-				continue
+			if strings.HasPrefix(msg.Content, "```") {
+				pages = append(pages, msg)
 			}
-		} else {
-			content += "\n" + msg.Content
+			continue
+		}
+		content += "\n" + msg.Content
+		if !slices.Contains(authors, msg.Author.Username) {
+			authors = append(authors, msg.Author.Username)
 		}
 		removableMessageIDs = append(removableMessageIDs, msg.ID)
 	}
+	slices.SortStableFunc(pages, func(a, b *discordgo.Message) int {
+		return a.Timestamp.Compare(b.Timestamp)
+	})
 	return
 }
 
+// PageIDs returns the message IDs of the pages of a published list or of other bot messages like expiry digests.
+func PageIDs(pages []*discordgo.Message) []string {
+	var ids []string
+	for _, page := range pages {
+		ids = append(ids, page.ID)
+	}
+	return ids
+}
+
+// LoadItems reads the current list from the database. An empty table is seeded once from the Markdown tables of the
+// given pages, so that an inventory which so far only existed as bot messages is not lost.
+func LoadItems(pantryClient model.PantryClient, pages []*discordgo.Message, dateFormat string) ([]model.PantryItem, error) {
+	items, err := pantryClient.GetItems()
+	if err != nil || len(items) != 0 {
+		return items, err
+	}
+
+	var seed []model.PantryItem
+	for _, page := range pages {
+		seed = append(seed, model.FromMarkdownTable(page.Content, dateFormat)...)
+	}
+	if len(seed) == 0 {
+		return items, nil
+	}
+	for index := range seed {
+		seed[index].Number = index + 1
+	}
+	log.Info().Msgf("Seeding empty pantry table with %d items from %d bot messages", len(seed), len(pages))
+	return pantryClient.ReplaceItems(seed)
+}
+
+// InitHistory records the current list as first revision, so that the very first change can be undone as well.
+func InitHistory(historyClient model.HistoryClient, items []model.PantryItem) error {
+	revisions, err := historyClient.GetRevisions(1)
+	if err != nil || len(revisions) != 0 {
+		return err
+	}
+	_, err = historyClient.AddRevision(model.Revision{
+		Author: initialRevisionActor,
+		Action: "initial state",
+		Items:  items,
+		Date:   time.Now(),
+	})
+	return err
+}
+
+// StoreItems persists the updated list and records the change as new revision of the list history. New items are
+// attributed to the author.
+func StoreItems(pantryClient model.PantryClient, historyClient model.HistoryClient, items, updatedItems []model.PantryItem, author string) ([]model.PantryItem, error) {
+	storedItems, err := pantryClient.ReplaceItems(AttributeItems(updatedItems, author))
+	if err != nil {
+		return nil, err
+	}
+	RecordRevision(historyClient, items, storedItems, author)
+	return storedItems, nil
+}
+
+// AttributeItems sets the author of all items, which are not stored yet and have no author.
+func AttributeItems(items []model.PantryItem, author string) []model.PantryItem {
+	attributedItems := slices.Clone(items)
+	for index := range attributedItems {
+		if attributedItems[index].ID == 0 && attributedItems[index].Author == "" {
+			attributedItems[index].Author = author
+		}
+	}
+	return attributedItems
+}
+
+// ItemsBy returns the items added by the given user, including items added together with other users. Numbers are
+// kept, so they still refer to the full list.
+func ItemsBy(items []model.PantryItem, author string) []model.PantryItem {
+	var authorItems []model.PantryItem
+	for _, item := range items {
+		if slices.Contains(strings.Split(item.Author, ", "), author) {
+			authorItems = append(authorItems, item)
+		}
+	}
+	return authorItems
+}
+
+// RecordRevision adds the stored list as new revision to the list history, unless nothing changed.
+func RecordRevision(historyClient model.HistoryClient, items, storedItems []model.PantryItem, author string) {
+	if action := model.DescribeChanges(items, storedItems); action != "" {
+		if _, err := historyClient.AddRevision(model.Revision{
+			Author: author,
+			Action: action,
+			Items:  storedItems,
+			Date:   time.Now(),
+		}); err != nil {
+			log.Error().Err(err).Msg("Could not record list revision")
+		}
+	}
+}
+
+// RestoreRevision moves the list history by one revision via `Undo` or `Redo` and stores the list of that revision.
+// The list stays untouched if there is nothing to undo or redo.
+func RestoreRevision(pantryClient model.PantryClient, move func() (*model.Revision, error)) ([]model.PantryItem, error) {
+	revision, err := move()
+	if err != nil {
+		return nil, err
+	}
+	if revision == nil {
+		return pantryClient.GetItems()
+	}
+	return pantryClient.ReplaceItems(revision.Items)
+}
+
 func UpdateItemsFromList(items []model.PantryItem, updatedList string) []model.PantryItem {
 	var updatedItems []model.PantryItem
 	var newItems []string
@@ -85,16 +201,27 @@ func UpdateItemsFromList(items []model.PantryItem, updatedList string) []model.P
 			newItems = append(newItems, item)
 			continue
 		}
-		var getOldItemDate = func(oldItems []model.PantryItem) time.Time {
+		var getOldItem = func(oldItems []model.PantryItem) model.PantryItem {
 			for _, oldItem := range oldItems {
 				if oldItem.Number == number {
-					return oldItem.Date
+					return oldItem
 				}
 			}
-			return time.Now().Truncate(time.Minute)
+			return model.PantryItem{Date: time.Now().Truncate(time.Minute)}
 		}
 
-		updatedItems = add(updatedItems, item, getOldItemDate(items))
+		oldItem := getOldItem(items)
+		// keep database identity of edited items unless the same number was entered twice
+		isTaken := slices.ContainsFunc(updatedItems, func(updatedItem model.PantryItem) bool {
+			return oldItem.ID != 0 && updatedItem.ID == oldItem.ID
+		})
+		updatedItems = add(updatedItems, item, oldItem.Date)
+		updatedItems[len(updatedItems)-1].Note = oldItem.Note
+		updatedItems[len(updatedItems)-1].Checked = oldItem.Checked
+		updatedItems[len(updatedItems)-1].Author = oldItem.Author
+		if !isTaken {
+			updatedItems[len(updatedItems)-1].ID = oldItem.ID
+		}
 	}
 
 	for _, newItem := range newItems {
@@ -113,30 +240,110 @@ func UpdateItems(items []model.PantryItem, content string) []model.PantryItem {
 		if removeRegex.MatchString(line) {
 			items = remove(items, line)
 		} else {
-			items = add(items, line, time.Now().Truncate(time.Minute))
+			items = merge(items, line, time.Now().Truncate(time.Minute))
 		}
 	}
 	return items
 }
 
+// merge parses a line like add, but increases the amount of an existing item with the same name instead of adding a
+// duplicate. Lines like `+2 eggs` or `eggs -1` adjust the amount of an existing item.
+func merge(items []model.PantryItem, line string, date time.Time) []model.PantryItem {
+	sign := 1.0
+	if adjustment := leadingAdjustment.FindStringSubmatch(line); adjustment != nil {
+		sign, line = adjustmentSign(adjustment[1]), adjustment[2]
+	} else if adjustment := trailingAdjustment.FindStringSubmatch(line); adjustment != nil {
+		sign, line = adjustmentSign(adjustment[2]), adjustment[1]+" "+adjustment[3]
+	}
+
+	item := add(nil, line, date)[0]
+	item.Amount *= sign
+	return MergeItem(items, item)
+}
+
+func adjustmentSign(sign string) float64 {
+	if sign == "-" {
+		return -1
+	}
+	return 1
+}
+
+// MergeItem adds the amount of the item to an existing item with the same normalized name and a convertible unit.
+// Items whose amount drops to zero are removed. A negative amount without matching item is ignored, any other item
+// is appended to the list.
+func MergeItem(items []model.PantryItem, item model.PantryItem) []model.PantryItem {
+	name := model.NormalizeName(item.Item, config.Config.Discord.Synonyms)
+	for index, existing := range items {
+		if model.NormalizeName(existing.Item, config.Config.Discord.Synonyms) != name {
+			continue
+		}
+		amount, ok := model.ConvertAmount(item.Amount, item.Unit, existing.Unit)
+		if !ok {
+			continue
+		}
+
+		mergedItems := append([]model.PantryItem{}, items...) // keep the original list intact for the history
+		merged := &mergedItems[index]
+		merged.Amount = model.AddAmount(merged.Amount, amount)
+		if !item.Expires.IsZero() && (merged.Expires.IsZero() || item.Expires.Before(merged.Expires)) {
+			merged.Expires = item.Expires // the first item to expire matters
+		}
+		if item.Category != "" {
+			merged.Category = item.Category
+		}
+		if amount > 0 {
+			merged.Checked = false // there is more to buy
+		}
+		if merged.Amount <= 0 {
+			return remove(mergedItems, strconv.Itoa(merged.Number))
+		}
+		return mergedItems
+	}
+
+	if item.Amount <= 0 {
+		return items
+	}
+	item.Number = len(items) + 1
+	return append(items, item)
+}
+
+// RemoveItems removes items by the same expression as typed into a channel, e.g. `3`, `1 4-7` or `* 2`.
+func RemoveItems(items []model.PantryItem, expression string) ([]model.PantryItem, error) {
+	expression = strings.TrimSpace(expression)
+	if expression == "" || !removeRegex.MatchString(expression) {
+		return nil, fmt.Errorf("invalid remove expression `%s`", expression)
+	}
+	return remove(items, expression), nil
+}
+
 func remove(items []model.PantryItem, line string) []model.PantryItem {
+	selected := selectNumbers(items, line)
 	result := make([]model.PantryItem, 0)
-	removeAllExcept := false
+	for _, entry := range items {
+		if slices.Contains(selected, entry.Number) {
+			continue
+		}
+		entry.Number = len(result) + 1
+		result = append(result, entry)
+	}
+	return result
+}
+
+// selectNumbers returns the numbers of all items matched by an expression like `2 4 6-9` or `* 2`.
+func selectNumbers(items []model.PantryItem, line string) []int {
+	selectAllExcept := false
 
 	// CAPTURE GROUP 0: entire string
 	// CAPTURE GROUP 1: asterisk
 	// CAPTURE GROUP 2: range
 	captureGroups := removeRegex.FindStringSubmatch(line)
 
-	// remove all (except)
+	// select all (except)
 	if captureGroups[1] == "*" {
-		removeAllExcept = true
-		if captureGroups[0] == captureGroups[1] {
-			return result
-		}
+		selectAllExcept = true
 	}
 
-	// add single removable numbers
+	// add single numbers
 	var numbers []int
 	if captureGroups[0] != captureGroups[2] {
 		for _, value := range strings.Split(captureGroups[0], " ") {
@@ -146,7 +353,7 @@ func remove(items []model.PantryItem, line string) []model.PantryItem {
 		}
 	}
 
-	// add range to removable numbers
+	// add range to numbers
 	if captureGroups[2] != "" {
 		range_ := strings.Split(captureGroups[2], "-")
 		rangeStart, _ := strconv.Atoi(range_[0])
@@ -157,116 +364,234 @@ func remove(items []model.PantryItem, line string) []model.PantryItem {
 		}
 	}
 
+	var selected []int
 	for _, entry := range items {
-		if slices.Contains(numbers, entry.Number) {
-			if !removeAllExcept {
-				continue
-			}
-		} else if removeAllExcept {
-			continue
+		if slices.Contains(numbers, entry.Number) != selectAllExcept {
+			selected = append(selected, entry.Number)
 		}
-		entry.Number = len(result) + 1
-		result = append(result, entry)
 	}
-	return result
+	return selected
 }
 
 func add(items []model.PantryItem, line string, date time.Time) []model.PantryItem {
-	leading := leadingQuantity.FindStringSubmatch(line)
-	trailing := trailingQuantity.FindStringSubmatch(line)
+	var category string
+	if match := categoryTagRegex.FindStringSubmatch(line); match != nil {
+		category = strings.ToLower(match[1])
+		line = strings.TrimSpace(categoryTagRegex.ReplaceAllString(line, ""))
+	}
 
-	var quantity string
-	if leading != nil {
-		quantity = leading[1]
-		line = strings.TrimPrefix(line, quantity)
-	} else if trailing != nil {
-		quantity = trailing[1]
-		line = strings.TrimSuffix(line, quantity)
+	var expires time.Time
+	if match := expiryRegex.FindStringSubmatch(line); match != nil {
+		if parsed, err := ParseExpiry(match[1]); err == nil {
+			expires = parsed
+			line = strings.TrimSuffix(line, match[0])
+		}
 	}
 
-	amount, err := strconv.Atoi(quantity)
-	if err != nil {
-		amount = 1
+	amount, unit := 1.0, ""
+	if leading := leadingQuantity.FindStringSubmatch(line); leading != nil {
+		amount, unit, _ = model.ParseQuantity(leading[1])
+		line = leading[2]
+	} else if trailing := trailingQuantity.FindStringSubmatch(line); trailing != nil {
+		amount, unit, _ = model.ParseQuantity(trailing[2])
+		line = trailing[1]
 	}
 
 	return append(items, model.PantryItem{
-		Number: len(items) + 1,
-		Item:   strings.TrimSpace(line),
-		Amount: amount,
-		Date:   date,
+		Number:   len(items) + 1,
+		Item:     strings.TrimSpace(line),
+		Amount:   amount,
+		Unit:     unit,
+		Date:     date,
+		Expires:  expires,
+		Category: category,
 	})
 }
 
-// PublishItems sends the latest []PantryItem state to the active channel (via new or edited message).
-// Because of a character limit of 2000, the function automatically splits the Markdown table line by line
-// and sends multiple messages to the channel. The last message always contains the buttons to interact with the bot.
-func PublishItems(items []model.PantryItem, session *discordgo.Session, channelID, messageID string, lineBreak int, dateFormat string) {
-	markdownTable := model.ToMarkdownTable(items, lineBreak, dateFormat)
+// ParseExpiry parses a best-before date like `31.03.27`, `31.03.2027` or `03.2027`. Dates without a day expire at
+// the end of the month.
+func ParseExpiry(value string) (time.Time, error) {
+	for _, layout := range []string{"2.1.2006", "2.1.06"} {
+		if expires, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return expires, nil
+		}
+	}
+	expires, err := time.ParseInLocation("1.2006", value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid best-before date `%s`", value)
+	}
+	return expires.AddDate(0, 1, -1), nil
+}
+
+// ToMarkdownTable renders items with the columns, line break and date format of the channel. Items without a tagged
+// category are shown in the category matched by their keywords.
+func ToMarkdownTable(items []model.PantryItem, channel config.Channel) string {
+	if slices.Contains(channel.Columns, model.CategoryColumn) {
+		items = slices.Clone(items)
+		for index := range items {
+			items[index].Category = model.CategoryOf(items[index], config.Config.Discord.Categories)
+		}
+	}
+	return model.ToMarkdownTable(items, channel.LineBreak, channel.DateFormat, channel.Columns...)
+}
+
+// PublishItems sends the latest []PantryItem state to the active channel. Because of a character limit of 2000,
+// the Markdown table is split into pages which are published as consecutive messages. Existing pages are edited in
+// place, missing pages are sent and surplus pages deleted. Only the last page contains the buttons to interact with
+// the bot. An expiry digest is moved below sent pages, so it never ends up between two pages.
+func PublishItems(items []model.PantryItem, session model.ChatSession, channel config.Channel, pageIDs []string) {
+	metrics.ListItems.WithLabelValues(channel.Name).Set(float64(len(items)))
+	tables := model.SplitMarkdownTable(ToMarkdownTable(items, channel), maxMessageLength)
+
+	for index, table := range tables {
+		components := []discordgo.MessageComponent{} // an empty slice removes buttons from former last pages
+		if index == len(tables)-1 {
+			components = CreateMessageButtons(channel) // buttons depend on the configured behaviours
+		}
 
-	if len(markdownTable) <= 2000 { // 2000 is the message length limit
-		if messageID != "" { // update existing message
-			editedMessage := discordgo.NewMessageEdit(channelID, messageID)
-			editedMessage.SetContent(markdownTable)
+		if index < len(pageIDs) { // update existing page
+			editedMessage := discordgo.NewMessageEdit(channel.ID, pageIDs[index])
+			editedMessage.SetContent(table)
+			editedMessage.Components = &components
 			if _, err := session.ChannelMessageEditComplex(editedMessage); err != nil {
-				log.Error().Err(err).Msgf("Could not edit message %s", messageID)
+				log.Error().Err(err).Msgf("Could not edit message %s", pageIDs[index])
 			}
 		} else {
-			if _, err := session.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
-				Content:    model.ToMarkdownTable(items, lineBreak, dateFormat),
-				Components: CreateMessageButtons(),
+			if _, err := session.ChannelMessageSendComplex(channel.ID, &discordgo.MessageSend{
+				Content:    table,
+				Components: components,
 			}); err != nil {
 				log.Error().Err(err).Msg("Could not send complex message")
 			}
 		}
-	} else { // split table line by line
-		markdownTableSplit := strings.Split(markdownTable, "\n")
-		tempTable := ""
-
-		for _, line := range markdownTableSplit {
-			if len(tempTable)+len(line) <= 1980 {
-				tempTable += line + "\n"
-				continue
-			}
-			tempTable += "...```"
+	}
 
-			if messageID != "" {
-				editedMessage := discordgo.NewMessageEdit(channelID, messageID)
-				editedMessage.SetContent(tempTable)
-				if _, err := session.ChannelMessageEditComplex(editedMessage); err != nil {
-					log.Error().Err(err).Msgf("Could not edit message %s", messageID)
-				}
-			} else {
-				if _, err := session.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
-					Content:    tempTable,
-					Components: CreateMessageButtons(),
-				}); err != nil {
-					log.Error().Err(err).Msg("Could not send complex message")
-				}
-			}
-			return
+	if len(pageIDs) > len(tables) {
+		deleteMessages(session, channel.ID, pageIDs[len(tables):])
+	}
+	if len(tables) > len(pageIDs) && channel.Expiry.Digest != "" {
+		moveExpiryDigests(session, channel.ID)
+	}
+}
+
+// deleteMessages deletes bot messages one by one, as Discord refuses to delete messages older than two weeks in
+// bulk and pages of a list usually are.
+func deleteMessages(session model.ChatSession, channelID string, messageIDs []string) {
+	for _, messageID := range messageIDs {
+		if err := session.ChannelMessageDelete(channelID, messageID); err != nil {
+			log.Error().Err(err).Msgf("Could not delete message %s", messageID)
 		}
 	}
 }
 
-func CreateMessageButtons() []discordgo.MessageComponent {
-	return []discordgo.MessageComponent{
-		discordgo.ActionsRow{
-			Components: []discordgo.MessageComponent{
-				discordgo.Button{
-					Emoji: &discordgo.ComponentEmoji{
-						Name: "📝",
-					},
-					Style:    discordgo.SecondaryButton,
-					CustomID: EditButton,
-				},
-				discordgo.Button{
-					Emoji: &discordgo.ComponentEmoji{
-						Name: "🔙",
-					},
-					Style:    discordgo.SecondaryButton,
-					CustomID: UndoButton,
-				},
-			},
+// CreateHistoryResponse lists the latest revisions of a list only to the user who requested it.
+func CreateHistoryResponse(historyClient model.HistoryClient) *discordgo.InteractionResponse {
+	content := "No history recorded yet"
+	revisions, err := historyClient.GetRevisions(historyViewSize)
+	if err != nil {
+		log.Error().Err(err).Msg("Could not load list history")
+		content = "Could not load history"
+	} else if len(revisions) != 0 {
+		content = model.ToHistoryTable(revisions, historyActionLength)
+	}
+
+	return &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
 		},
 	}
 }
+
+// InteractionAuthor returns the name of the user who triggered an interaction.
+func InteractionAuthor(interaction *discordgo.InteractionCreate) string {
+	if interaction.Member != nil && interaction.Member.User != nil {
+		return interaction.Member.User.Username
+	} else if interaction.User != nil {
+		return interaction.User.Username
+	}
+	return "unknown"
+}
+
+// CreateMessageButtons creates the buttons for all behaviours enabled in the channel.
+func CreateMessageButtons(channel config.Channel) []discordgo.MessageComponent {
+	var buttons []discordgo.MessageComponent
+	if channel.HasBehaviour(EditBehaviour) {
+		buttons = append(buttons, discordgo.Button{
+			Emoji: &discordgo.ComponentEmoji{
+				Name: "📝",
+			},
+			Style:    discordgo.SecondaryButton,
+			CustomID: EditButton,
+		})
+	}
+	if channel.HasBehaviour(UndoBehaviour) {
+		buttons = append(buttons, discordgo.Button{
+			Emoji: &discordgo.ComponentEmoji{
+				Name: "🔙",
+			},
+			Style:    discordgo.SecondaryButton,
+			CustomID: UndoButton,
+		}, discordgo.Button{
+			Emoji: &discordgo.ComponentEmoji{
+				Name: "🔜",
+			},
+			Style:    discordgo.SecondaryButton,
+			CustomID: RedoButton,
+		})
+	}
+	if channel.HasBehaviour(HistoryBehaviour) {
+		buttons = append(buttons, discordgo.Button{
+			Emoji: &discordgo.ComponentEmoji{
+				Name: "📜",
+			},
+			Style:    discordgo.SecondaryButton,
+			CustomID: HistoryButton,
+		})
+	}
+	if channel.HasBehaviour(MoveBehaviour) {
+		buttons = append(buttons, discordgo.Button{
+			Emoji: &discordgo.ComponentEmoji{
+				Name: "🚚",
+			},
+			Style:    discordgo.SecondaryButton,
+			CustomID: MoveButton,
+		})
+	}
+
+	if channel.HasBehaviour(ShoppingBehaviour) {
+		buttons = append(buttons, discordgo.Button{
+			Emoji: &discordgo.ComponentEmoji{
+				Name: "🛒",
+			},
+			Style:    discordgo.SecondaryButton,
+			CustomID: TickButton,
+		}, discordgo.Button{
+			Label: "Finish trip",
+			Emoji: &discordgo.ComponentEmoji{
+				Name: "✅",
+			},
+			Style:    discordgo.SuccessButton,
+			CustomID: FinishTripButton,
+		})
+	}
+
+	if channel.HasBehaviour(SuggestBehaviour) {
+		buttons = append(buttons, discordgo.Button{
+			Emoji: &discordgo.ComponentEmoji{
+				Name: "💡",
+			},
+			Style:    discordgo.SecondaryButton,
+			CustomID: SuggestButton,
+		})
+	}
+
+	rows := []discordgo.MessageComponent{}
+	for start := 0; start < len(buttons); start += maxButtonsPerRow {
+		rows = append(rows, discordgo.ActionsRow{
+			Components: buttons[start:min(start+maxButtonsPerRow, len(buttons))],
+		})
+	}
+	return rows
+}
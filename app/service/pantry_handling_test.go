@@ -23,6 +23,7 @@ func TestUpdateFromList(t *testing.T) {
 					Item:   "bacon",
 					Amount: 1,
 					Date:   time.Date(time.Now().Year(), 12, 27, 0, 0, 0, 0, time.Local),
+					Author: "mari",
 				},
 			},
 			update: "[1] 3 bacon\n",
@@ -33,6 +34,7 @@ func TestUpdateFromList(t *testing.T) {
 					Item:   "bacon",
 					Amount: 3,
 					Date:   time.Date(time.Now().Year(), 12, 27, 0, 0, 0, 0, time.Local),
+					Author: "mari",
 				},
 			},
 		},
@@ -195,44 +197,44 @@ func TestRemove(t *testing.T) {
 		"single remove": {
 			content: "7",
 			expected: []model.PantryItem{
-				{0, 1, "item", 1, time.Now().Truncate(time.Minute)},
-				{0, 2, "item", 2, time.Now().Truncate(time.Minute)},
-				{0, 3, "item", 3, time.Now().Truncate(time.Minute)},
-				{0, 4, "item", 4, time.Now().Truncate(time.Minute)},
-				{0, 5, "item", 5, time.Now().Truncate(time.Minute)},
-				{0, 6, "item", 6, time.Now().Truncate(time.Minute)},
-				{0, 7, "item", 8, time.Now().Truncate(time.Minute)},
-				{0, 8, "item", 9, time.Now().Truncate(time.Minute)},
+				{Number: 1, Item: "item", Amount: 1, Date: time.Now().Truncate(time.Minute)},
+				{Number: 2, Item: "item", Amount: 2, Date: time.Now().Truncate(time.Minute)},
+				{Number: 3, Item: "item", Amount: 3, Date: time.Now().Truncate(time.Minute)},
+				{Number: 4, Item: "item", Amount: 4, Date: time.Now().Truncate(time.Minute)},
+				{Number: 5, Item: "item", Amount: 5, Date: time.Now().Truncate(time.Minute)},
+				{Number: 6, Item: "item", Amount: 6, Date: time.Now().Truncate(time.Minute)},
+				{Number: 7, Item: "item", Amount: 8, Date: time.Now().Truncate(time.Minute)},
+				{Number: 8, Item: "item", Amount: 9, Date: time.Now().Truncate(time.Minute)},
 			},
 		},
 		"multi remove": {
 			content: "3 5 8",
 			expected: []model.PantryItem{
-				{0, 1, "item", 1, time.Now().Truncate(time.Minute)},
-				{0, 2, "item", 2, time.Now().Truncate(time.Minute)},
-				{0, 3, "item", 4, time.Now().Truncate(time.Minute)},
-				{0, 4, "item", 6, time.Now().Truncate(time.Minute)},
-				{0, 5, "item", 7, time.Now().Truncate(time.Minute)},
-				{0, 6, "item", 9, time.Now().Truncate(time.Minute)},
+				{Number: 1, Item: "item", Amount: 1, Date: time.Now().Truncate(time.Minute)},
+				{Number: 2, Item: "item", Amount: 2, Date: time.Now().Truncate(time.Minute)},
+				{Number: 3, Item: "item", Amount: 4, Date: time.Now().Truncate(time.Minute)},
+				{Number: 4, Item: "item", Amount: 6, Date: time.Now().Truncate(time.Minute)},
+				{Number: 5, Item: "item", Amount: 7, Date: time.Now().Truncate(time.Minute)},
+				{Number: 6, Item: "item", Amount: 9, Date: time.Now().Truncate(time.Minute)},
 			},
 		},
 		"single and range remove": {
 			content: "1 4-7",
 			expected: []model.PantryItem{
-				{0, 1, "item", 2, time.Now().Truncate(time.Minute)},
-				{0, 2, "item", 3, time.Now().Truncate(time.Minute)},
-				{0, 3, "item", 8, time.Now().Truncate(time.Minute)},
-				{0, 4, "item", 9, time.Now().Truncate(time.Minute)},
+				{Number: 1, Item: "item", Amount: 2, Date: time.Now().Truncate(time.Minute)},
+				{Number: 2, Item: "item", Amount: 3, Date: time.Now().Truncate(time.Minute)},
+				{Number: 3, Item: "item", Amount: 8, Date: time.Now().Truncate(time.Minute)},
+				{Number: 4, Item: "item", Amount: 9, Date: time.Now().Truncate(time.Minute)},
 			},
 		},
 		"range remove": {
 			content: "2-5",
 			expected: []model.PantryItem{
-				{0, 1, "item", 1, time.Now().Truncate(time.Minute)},
-				{0, 2, "item", 6, time.Now().Truncate(time.Minute)},
-				{0, 3, "item", 7, time.Now().Truncate(time.Minute)},
-				{0, 4, "item", 8, time.Now().Truncate(time.Minute)},
-				{0, 5, "item", 9, time.Now().Truncate(time.Minute)},
+				{Number: 1, Item: "item", Amount: 1, Date: time.Now().Truncate(time.Minute)},
+				{Number: 2, Item: "item", Amount: 6, Date: time.Now().Truncate(time.Minute)},
+				{Number: 3, Item: "item", Amount: 7, Date: time.Now().Truncate(time.Minute)},
+				{Number: 4, Item: "item", Amount: 8, Date: time.Now().Truncate(time.Minute)},
+				{Number: 5, Item: "item", Amount: 9, Date: time.Now().Truncate(time.Minute)},
 			},
 		},
 		"remove all": {
@@ -242,33 +244,33 @@ func TestRemove(t *testing.T) {
 		"remove all except single": {
 			content: "* 5",
 			expected: []model.PantryItem{
-				{0, 1, "item", 5, time.Now().Truncate(time.Minute)},
+				{Number: 1, Item: "item", Amount: 5, Date: time.Now().Truncate(time.Minute)},
 			},
 		},
 		"remove all except multi": {
 			content: "* 5 2 8",
 			expected: []model.PantryItem{
-				{0, 1, "item", 2, time.Now().Truncate(time.Minute)},
-				{0, 2, "item", 5, time.Now().Truncate(time.Minute)},
-				{0, 3, "item", 8, time.Now().Truncate(time.Minute)},
+				{Number: 1, Item: "item", Amount: 2, Date: time.Now().Truncate(time.Minute)},
+				{Number: 2, Item: "item", Amount: 5, Date: time.Now().Truncate(time.Minute)},
+				{Number: 3, Item: "item", Amount: 8, Date: time.Now().Truncate(time.Minute)},
 			},
 		},
 		"remove all except range": {
 			content: "* 3-6",
 			expected: []model.PantryItem{
-				{0, 1, "item", 3, time.Now().Truncate(time.Minute)},
-				{0, 2, "item", 4, time.Now().Truncate(time.Minute)},
-				{0, 3, "item", 5, time.Now().Truncate(time.Minute)},
-				{0, 4, "item", 6, time.Now().Truncate(time.Minute)},
+				{Number: 1, Item: "item", Amount: 3, Date: time.Now().Truncate(time.Minute)},
+				{Number: 2, Item: "item", Amount: 4, Date: time.Now().Truncate(time.Minute)},
+				{Number: 3, Item: "item", Amount: 5, Date: time.Now().Truncate(time.Minute)},
+				{Number: 4, Item: "item", Amount: 6, Date: time.Now().Truncate(time.Minute)},
 			},
 		},
 		"remove all except single and range": {
 			content: "* 7 1-3",
 			expected: []model.PantryItem{
-				{0, 1, "item", 1, time.Now().Truncate(time.Minute)},
-				{0, 2, "item", 2, time.Now().Truncate(time.Minute)},
-				{0, 3, "item", 3, time.Now().Truncate(time.Minute)},
-				{0, 4, "item", 7, time.Now().Truncate(time.Minute)},
+				{Number: 1, Item: "item", Amount: 1, Date: time.Now().Truncate(time.Minute)},
+				{Number: 2, Item: "item", Amount: 2, Date: time.Now().Truncate(time.Minute)},
+				{Number: 3, Item: "item", Amount: 3, Date: time.Now().Truncate(time.Minute)},
+				{Number: 4, Item: "item", Amount: 7, Date: time.Now().Truncate(time.Minute)},
 			},
 		},
 	}
@@ -298,27 +300,67 @@ func TestAdd(t *testing.T) {
 	}{
 		"simple add": {
 			content:  "bacon",
-			expected: []model.PantryItem{{0, 1, "bacon", 1, time.Now().Truncate(time.Minute)}},
+			expected: []model.PantryItem{{Number: 1, Item: "bacon", Amount: 1, Date: time.Now().Truncate(time.Minute)}},
 		},
 		"simple multi word add": {
 			content:  "butter scotch",
-			expected: []model.PantryItem{{0, 1, "butter scotch", 1, time.Now().Truncate(time.Minute)}},
+			expected: []model.PantryItem{{Number: 1, Item: "butter scotch", Amount: 1, Date: time.Now().Truncate(time.Minute)}},
 		},
 		"simple hyphened add": {
 			content:  "dry-gin",
-			expected: []model.PantryItem{{0, 1, "dry-gin", 1, time.Now().Truncate(time.Minute)}},
+			expected: []model.PantryItem{{Number: 1, Item: "dry-gin", Amount: 1, Date: time.Now().Truncate(time.Minute)}},
 		},
 		"add with trailing quantity": {
 			content:  "bacon 5",
-			expected: []model.PantryItem{{0, 1, "bacon", 5, time.Now().Truncate(time.Minute)}},
+			expected: []model.PantryItem{{Number: 1, Item: "bacon", Amount: 5, Date: time.Now().Truncate(time.Minute)}},
 		},
 		"add with leading quantity": {
 			content:  "13 bacon",
-			expected: []model.PantryItem{{0, 1, "bacon", 13, time.Now().Truncate(time.Minute)}},
+			expected: []model.PantryItem{{Number: 1, Item: "bacon", Amount: 13, Date: time.Now().Truncate(time.Minute)}},
 		},
 		"add with numbered name": {
 			content:  "2 monkey47",
-			expected: []model.PantryItem{{0, 1, "monkey47", 2, time.Now().Truncate(time.Minute)}},
+			expected: []model.PantryItem{{Number: 1, Item: "monkey47", Amount: 2, Date: time.Now().Truncate(time.Minute)}},
+		},
+		"add with leading unit": {
+			content:  "500g flour",
+			expected: []model.PantryItem{{Number: 1, Item: "flour", Amount: 500, Unit: "g", Date: time.Now().Truncate(time.Minute)}},
+		},
+		"add with fractional quantity": {
+			content:  "1.5 l milk",
+			expected: []model.PantryItem{{Number: 1, Item: "milk", Amount: 1.5, Unit: "l", Date: time.Now().Truncate(time.Minute)}},
+		},
+		"add with category tag": {
+			content:  "oat milk #Dairy 2",
+			expected: []model.PantryItem{{Number: 1, Item: "oat milk", Amount: 2, Date: time.Now().Truncate(time.Minute), Category: "dairy"}},
+		},
+		"add with leading category tag": {
+			content:  "#bakery 500g flour",
+			expected: []model.PantryItem{{Number: 1, Item: "flour", Amount: 500, Unit: "g", Date: time.Now().Truncate(time.Minute), Category: "bakery"}},
+		},
+		"add with trailing unit": {
+			content:  "oat milk 0,5L",
+			expected: []model.PantryItem{{Number: 1, Item: "oat milk", Amount: 0.5, Unit: "l", Date: time.Now().Truncate(time.Minute)}},
+		},
+		"add with multiplier": {
+			content:  "2x pizza",
+			expected: []model.PantryItem{{Number: 1, Item: "pizza", Amount: 2, Date: time.Now().Truncate(time.Minute)}},
+		},
+		"add with unit lookalike": {
+			content:  "2 lemons",
+			expected: []model.PantryItem{{Number: 1, Item: "lemons", Amount: 2, Date: time.Now().Truncate(time.Minute)}},
+		},
+		"add with best-before month": {
+			content:  "salmon 2 exp 03.2027",
+			expected: []model.PantryItem{{Number: 1, Item: "salmon", Amount: 2, Date: time.Now().Truncate(time.Minute), Expires: time.Date(2027, 3, 31, 0, 0, 0, 0, time.Local)}},
+		},
+		"add with best-before date": {
+			content:  "2 salmon EXP 14.3.27",
+			expected: []model.PantryItem{{Number: 1, Item: "salmon", Amount: 2, Date: time.Now().Truncate(time.Minute), Expires: time.Date(2027, 3, 14, 0, 0, 0, 0, time.Local)}},
+		},
+		"add with invalid best-before date": {
+			content:  "salmon exp 32.13.27",
+			expected: []model.PantryItem{{Number: 1, Item: "salmon exp 32.13.27", Amount: 1, Date: time.Now().Truncate(time.Minute)}},
 		},
 	}
 
@@ -332,3 +374,109 @@ func TestAdd(t *testing.T) {
 		})
 	}
 }
+
+func TestMerge(t *testing.T) {
+	// given
+	date := time.Now().Truncate(time.Minute)
+	items := []model.PantryItem{
+		{ID: 1, Number: 1, Item: "eggs", Amount: 3, Date: date},
+		{ID: 2, Number: 2, Item: "flour", Amount: 500, Unit: "g", Date: date},
+		{ID: 3, Number: 3, Item: "salmon", Amount: 2, Date: date, Expires: time.Date(2027, 3, 31, 0, 0, 0, 0, time.Local)},
+	}
+	tests := map[string]struct {
+		content  string
+		expected []model.PantryItem
+	}{
+		"re-add existing item": {
+			content: "Egg 3",
+			expected: []model.PantryItem{
+				{ID: 1, Number: 1, Item: "eggs", Amount: 6, Date: date}, items[1], items[2],
+			},
+		},
+		"increase amount": {
+			content: "+2 eggs",
+			expected: []model.PantryItem{
+				{ID: 1, Number: 1, Item: "eggs", Amount: 5, Date: date}, items[1], items[2],
+			},
+		},
+		"decrease amount": {
+			content: "eggs -1",
+			expected: []model.PantryItem{
+				{ID: 1, Number: 1, Item: "eggs", Amount: 2, Date: date}, items[1], items[2],
+			},
+		},
+		"take out last items": {
+			content: "-2 salmon",
+			expected: []model.PantryItem{
+				items[0], items[1],
+			},
+		},
+		"take out missing item": {
+			content:  "-1 pizza",
+			expected: items,
+		},
+		"add convertible unit": {
+			content: "1.5 kg flour",
+			expected: []model.PantryItem{
+				items[0], {ID: 2, Number: 2, Item: "flour", Amount: 2000, Unit: "g", Date: date}, items[2],
+			},
+		},
+		"add other unit": {
+			content: "2 packs flour",
+			expected: []model.PantryItem{
+				items[0], items[1], items[2], {Number: 4, Item: "flour", Amount: 2, Unit: "packs", Date: date},
+			},
+		},
+		"keep first best-before date": {
+			content: "salmon 1 exp 01.2027",
+			expected: []model.PantryItem{
+				items[0], items[1], {ID: 3, Number: 3, Item: "salmon", Amount: 3, Date: date, Expires: time.Date(2027, 1, 31, 0, 0, 0, 0, time.Local)},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// when
+			actual := merge(items, test.content, date)
+
+			// then
+			assert.EqualValues(t, test.expected, actual)
+			assert.Equal(t, 3.0, items[0].Amount) // original list stays untouched
+		})
+	}
+}
+
+func TestAttributeItems(t *testing.T) {
+	// given
+	items := []model.PantryItem{
+		{ID: 1, Item: "eggs", Author: "tom"},
+		{ID: 2, Item: "milk"},
+		{Item: "coffee"},
+		{Item: "pizza", Author: "restock"},
+	}
+
+	// when
+	actual := AttributeItems(items, "mari")
+
+	// then
+	assert.Equal(t, []string{"tom", "", "mari", "restock"}, []string{actual[0].Author, actual[1].Author, actual[2].Author, actual[3].Author})
+	assert.Empty(t, items[2].Author)
+}
+
+func TestItemsBy(t *testing.T) {
+	// given
+	items := []model.PantryItem{
+		{Number: 1, Item: "eggs", Author: "mari"},
+		{Number: 2, Item: "the blue sauce", Author: "tom, mari"},
+		{Number: 3, Item: "coffee", Author: "tom"},
+		{Number: 4, Item: "milk"},
+	}
+
+	// when
+	actual := ItemsBy(items, "mari")
+
+	// then
+	assert.Equal(t, []model.PantryItem{items[0], items[1]}, actual)
+	assert.Empty(t, ItemsBy(items, "mar"))
+}
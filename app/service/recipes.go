@@ -0,0 +1,173 @@
+package service
+
+import (
+	"fmt"
+	"github.com/bwmarrin/discordgo"
+	"github.com/maribowman/roastbeef-swag/app/model"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	RecipeCommand     = "recipe"
+	NameOption        = "name"
+	IngredientsOption = "ingredients"
+)
+
+var recipeRegex = regexp.MustCompile(`(?i)^recipe\s+(.+?)(?:\s+x\s*(\d+(?:[.,]\d+)?))?$`)
+
+// recipeRequest is a line like `recipe lasagne x2`.
+type recipeRequest struct {
+	line   string
+	name   string
+	factor float64
+}
+
+// splitRecipes separates lines like `recipe lasagne x2` from the other content of a message.
+func splitRecipes(content string) ([]recipeRequest, string) {
+	var requests []recipeRequest
+	var lines []string
+	for _, line := range strings.Split(content, "\n") {
+		if match := recipeRegex.FindStringSubmatch(strings.TrimSpace(line)); match != nil {
+			factor := 1.0
+			if match[2] != "" {
+				factor, _ = strconv.ParseFloat(strings.ReplaceAll(match[2], ",", "."), 64)
+			}
+			if factor > 0 {
+				requests = append(requests, recipeRequest{line: line, name: strings.TrimSpace(match[1]), factor: factor})
+				continue
+			}
+		}
+		lines = append(lines, line)
+	}
+	return requests, strings.Join(lines, "\n")
+}
+
+// MergeRecipes combines the recipes of the config with the stored ones. Stored recipes replace configured recipes of
+// the same name.
+func MergeRecipes(configured map[string][]string, stored []model.Recipe) []model.Recipe {
+	recipes := slices.Clone(stored)
+	for name, ingredients := range configured {
+		if _, ok := FindRecipe(stored, name); !ok {
+			recipes = append(recipes, model.Recipe{Name: name, Ingredients: ingredients})
+		}
+	}
+	slices.SortFunc(recipes, func(a, b model.Recipe) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+	return recipes
+}
+
+// FindRecipe looks up a recipe by its name, ignoring case.
+func FindRecipe(recipes []model.Recipe, name string) (model.Recipe, bool) {
+	for _, recipe := range recipes {
+		if strings.EqualFold(recipe.Name, strings.TrimSpace(name)) {
+			return recipe, true
+		}
+	}
+	return model.Recipe{}, false
+}
+
+// ParseIngredients splits ingredients typed like `500g minced meat; 2 onions` into single ingredients.
+func ParseIngredients(value string) []string {
+	var ingredients []string
+	for _, ingredient := range strings.FieldsFunc(value, func(r rune) bool { return r == ';' || r == '\n' }) {
+		if ingredient = strings.TrimSpace(ingredient); ingredient != "" {
+			ingredients = append(ingredients, ingredient)
+		}
+	}
+	return ingredients
+}
+
+// AddRecipe adds the ingredients of a recipe, scaled by the factor, to the list. Ingredients in stock, e.g. in the
+// freezer, are subtracted first, so only what is missing is added. Stock used by one ingredient is not counted again
+// for another one. New items are noted with the name of the recipe.
+func AddRecipe(items []model.PantryItem, recipe model.Recipe, factor float64, stock []model.PantryItem, synonyms map[string]string) []model.PantryItem {
+	return addRecipe(items, recipe, factor, slices.Clone(stock), synonyms)
+}
+
+// addRecipe works like AddRecipe, but uses up the given stock, so that several recipes don't count the same stock.
+func addRecipe(items []model.PantryItem, recipe model.Recipe, factor float64, stock []model.PantryItem, synonyms map[string]string) []model.PantryItem {
+	date := time.Now().Truncate(time.Minute)
+	for _, line := range recipe.Ingredients {
+		ingredient := add(nil, line, date)[0]
+		ingredient.Amount = model.AddAmount(0, ingredient.Amount*factor)
+		ingredient.Note = recipe.Name
+
+		name := model.NormalizeName(ingredient.Item, synonyms)
+		for index := range stock {
+			if ingredient.Amount <= 0 || model.NormalizeName(stock[index].Item, synonyms) != name {
+				continue
+			}
+			available, ok := model.ConvertAmount(stock[index].Amount, stock[index].Unit, ingredient.Unit)
+			if !ok || available <= 0 {
+				continue
+			}
+			used := min(available, ingredient.Amount)
+			ingredient.Amount = model.AddAmount(ingredient.Amount, -used)
+			used, _ = model.ConvertAmount(used, ingredient.Unit, stock[index].Unit)
+			stock[index].Amount = model.AddAmount(stock[index].Amount, -used)
+		}
+		if ingredient.Amount > 0 {
+			items = MergeItem(items, ingredient)
+		}
+	}
+	return items
+}
+
+// ExecuteRecipeCommand applies a `/recipe` subcommand to the stored recipes and returns a reply. Recipes of the config
+// are listed, but can only be changed in the config.
+func ExecuteRecipeCommand(data discordgo.ApplicationCommandInteractionData, recipeClient model.RecipeClient, configured map[string][]string, author string) (string, error) {
+	if len(data.Options) == 0 {
+		return "", fmt.Errorf("missing subcommand")
+	}
+	subcommand := data.Options[0]
+	options := CommandOptions(data)
+
+	switch subcommand.Name {
+	case AddSubcommand:
+		recipe := model.Recipe{
+			Name:        strings.ToLower(strings.TrimSpace(options[NameOption].StringValue())),
+			Ingredients: ParseIngredients(options[IngredientsOption].StringValue()),
+			Author:      author,
+		}
+		if recipe.Name == "" || len(recipe.Ingredients) == 0 {
+			return "", fmt.Errorf("a recipe needs a name and ingredients like `500g minced meat; 2 onions`")
+		}
+		if err := recipeClient.SaveRecipe(recipe); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Saved recipe `%s` with %d ingredients", recipe.Name, len(recipe.Ingredients)), nil
+	case ListSubcommand:
+		stored, err := recipeClient.GetRecipes()
+		if err != nil {
+			return "", err
+		}
+		recipes := MergeRecipes(configured, stored)
+		if len(recipes) == 0 {
+			return "There are no recipes", nil
+		}
+		return model.ToRecipeList(recipes), nil
+	case DeleteSubcommand:
+		name := strings.TrimSpace(options[NameOption].StringValue())
+		stored, err := recipeClient.GetRecipes()
+		if err != nil {
+			return "", err
+		}
+		recipe, ok := FindRecipe(stored, name)
+		if !ok {
+			if _, ok := FindRecipe(MergeRecipes(configured, nil), name); ok {
+				return "", fmt.Errorf("recipe `%s` is configured and can only be removed from the config", name)
+			}
+			return "", fmt.Errorf("there is no recipe `%s`", name)
+		}
+		if err := recipeClient.RemoveRecipe(recipe.Name); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Deleted recipe `%s`", recipe.Name), nil
+	}
+	return "", fmt.Errorf("unknown subcommand `%s`", subcommand.Name)
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"github.com/maribowman/roastbeef-swag/app/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSplitRecipes(t *testing.T) {
+	// when
+	recipes, content := splitRecipes("eggs 3\nrecipe lasagne x2\nRecipe chili con carne\nrecipe soup x0,5\n2")
+
+	// then
+	assert.Equal(t, []recipeRequest{
+		{line: "recipe lasagne x2", name: "lasagne", factor: 2},
+		{line: "Recipe chili con carne", name: "chili con carne", factor: 1},
+		{line: "recipe soup x0,5", name: "soup", factor: 0.5},
+	}, recipes)
+	assert.Equal(t, "eggs 3\n2", content)
+}
+
+func TestMergeRecipes(t *testing.T) {
+	// given
+	configured := map[string][]string{
+		"lasagne": {"500g minced meat"},
+		"chili":   {"2 onions"},
+	}
+	stored := []model.Recipe{{Name: "Lasagne", Ingredients: []string{"400g minced meat"}, Author: "Mari"}}
+
+	// when
+	recipes := MergeRecipes(configured, stored)
+
+	// then
+	assert.Equal(t, []model.Recipe{
+		{Name: "chili", Ingredients: []string{"2 onions"}},
+		{Name: "Lasagne", Ingredients: []string{"400g minced meat"}, Author: "Mari"},
+	}, recipes)
+}
+
+func TestParseIngredients(t *testing.T) {
+	assert.Equal(t, []string{"500g minced meat", "2 onions", "lasagne sheets"}, ParseIngredients(" 500g minced meat;2 onions ;; lasagne sheets\n"))
+}
+
+func TestAddRecipe(t *testing.T) {
+	// given
+	recipe := model.Recipe{Name: "lasagne", Ingredients: []string{"500g minced meat", "2 onions", "1 lasagne sheets", "2 tomatoes"}}
+	items := []model.PantryItem{{ID: 1, Number: 1, Item: "lasagne sheets", Amount: 1}}
+	stock := []model.PantryItem{
+		{ID: 7, Number: 1, Item: "ground beef", Amount: 0.3, Unit: "kg"},
+		{ID: 8, Number: 2, Item: "onion", Amount: 5},
+		{ID: 9, Number: 3, Item: "tomatoes", Amount: 1},
+	}
+	synonyms := map[string]string{"minced meat": "ground beef"}
+
+	// when
+	actual := AddRecipe(items, recipe, 2, stock, synonyms)
+
+	// then
+	assert.Len(t, actual, 3)
+	assert.Equal(t, "lasagne sheets", actual[0].Item)
+	assert.Equal(t, 3.0, actual[0].Amount)
+	assert.Empty(t, actual[0].Note)
+	assert.Equal(t, "minced meat", actual[1].Item)
+	assert.Equal(t, 700.0, actual[1].Amount)
+	assert.Equal(t, "g", actual[1].Unit)
+	assert.Equal(t, "lasagne", actual[1].Note)
+	assert.Equal(t, "tomatoes", actual[2].Item)
+	assert.Equal(t, 3.0, actual[2].Amount)
+
+	// and
+	assert.Equal(t, 0.3, stock[0].Amount, "stock must not change")
+}
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"fmt"
+	"github.com/bwmarrin/discordgo"
+	"github.com/maribowman/roastbeef-swag/app/model"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ScheduleCommand   = "schedule"
+	PauseSubcommand   = "pause"
+	ResumeSubcommand  = "resume"
+	EntryOption       = "entry"
+	ScheduleIDOption  = "id"
+	recurringActor    = "schedule"
+	recurringClock    = "08:00"
+	recurringInterval = time.Minute
+)
+
+var (
+	recurringRegex  = regexp.MustCompile(`(?i)^(.+?)\s+every\s+(.+)$`)
+	recurrenceRegex = regexp.MustCompile(`(?i)^(?:(\d+)\s+)?(\w+?)s?(?:\s+at\s+(\d{1,2}:\d{2}))?$`)
+	weekdays        = map[string]time.Weekday{
+		"sunday": time.Sunday, "monday": time.Monday, "tuesday": time.Tuesday, "wednesday": time.Wednesday,
+		"thursday": time.Thursday, "friday": time.Friday, "saturday": time.Saturday,
+	}
+)
+
+// ParseSchedule parses an entry like `milk 2 every monday` or `toilet paper every 3 weeks at 18:00` into a schedule,
+// which is due for the first time after now.
+func ParseSchedule(entry string, now time.Time) (model.Schedule, error) {
+	match := recurringRegex.FindStringSubmatch(strings.TrimSpace(entry))
+	if match == nil {
+		return model.Schedule{}, fmt.Errorf("`%s` is not like `milk 2 every monday`", entry)
+	}
+	every := strings.ToLower(match[2])
+	next, err := NextRun(every, now)
+	if err != nil {
+		return model.Schedule{}, err
+	}
+	return model.Schedule{Item: strings.TrimSpace(match[1]), Every: every, Next: next, Created: now}, nil
+}
+
+// NextRun returns the next time after the given one a recurrence like `monday`, `day`, `3 weeks` or `month at 18:00`
+// is due. Weekdays are due on the next such day, intervals once the interval has passed. Runs are at 08:00 unless
+// another time is given.
+func NextRun(every string, after time.Time) (time.Time, error) {
+	match := recurrenceRegex.FindStringSubmatch(strings.TrimSpace(every))
+	if match == nil {
+		return time.Time{}, fmt.Errorf("invalid recurrence `%s`", every)
+	}
+	count := 1
+	if match[1] != "" {
+		count, _ = strconv.Atoi(match[1])
+	}
+	at := recurringClock
+	if match[3] != "" {
+		at = match[3]
+	}
+	clock, err := time.Parse("15:04", at)
+	if err != nil || count < 1 {
+		return time.Time{}, fmt.Errorf("invalid recurrence `%s`", every)
+	}
+	day := time.Date(after.Year(), after.Month(), after.Day(), clock.Hour(), clock.Minute(), 0, 0, after.Location())
+
+	unit := strings.ToLower(match[2])
+	if weekday, ok := weekdays[unit]; ok && match[1] == "" {
+		for !day.After(after) || day.Weekday() != weekday {
+			day = day.AddDate(0, 0, 1)
+		}
+		return day, nil
+	}
+	switch unit {
+	case "day":
+		return day.AddDate(0, 0, count), nil
+	case "week":
+		return day.AddDate(0, 0, 7*count), nil
+	case "month":
+		return day.AddDate(0, count, 0), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid recurrence `%s`, use a weekday or days, weeks or months", every)
+}
+
+// SyncSchedules stores the recurring items of the config as schedules of a list. Configured schedules keep their next
+// run and paused state across restarts, those no longer in the config are removed.
+func SyncSchedules(scheduleClient model.ScheduleClient, entries []string, now time.Time) error {
+	var configured []model.Schedule
+	for _, entry := range entries {
+		schedule, err := ParseSchedule(entry, now)
+		if err != nil {
+			return err
+		}
+		schedule.Author = recurringActor
+		configured = append(configured, schedule)
+	}
+
+	stored, err := scheduleClient.GetSchedules()
+	if err != nil {
+		return err
+	}
+	for _, schedule := range stored {
+		if isConfigured(schedule) && !slices.ContainsFunc(configured, func(entry model.Schedule) bool {
+			return entry.Item == schedule.Item && entry.Every == schedule.Every
+		}) {
+			if err := scheduleClient.RemoveSchedule(schedule.ID); err != nil {
+				return err
+			}
+		}
+	}
+	for _, schedule := range configured {
+		if _, err := scheduleClient.AddSchedule(schedule); err != nil { // already stored schedules stay untouched
+			return err
+		}
+	}
+	return nil
+}
+
+func isConfigured(schedule model.Schedule) bool {
+	return schedule.Author == recurringActor
+}
+
+// AddRecurringItems adds the items of all due schedules to the list and moves the schedules to their next run. Runs
+// missed while the bot was offline are caught up with a single addition.
+func AddRecurringItems(items []model.PantryItem, schedules []model.Schedule, now time.Time) ([]model.PantryItem, []model.Schedule) {
+	var due []model.Schedule
+	for _, schedule := range schedules {
+		if !isDue(schedule, now) {
+			continue
+		}
+		next, err := NextRun(schedule.Every, now)
+		if err != nil {
+			continue
+		}
+		items = merge(items, schedule.Item, now.Truncate(time.Minute))
+		schedule.Next = next
+		due = append(due, schedule)
+	}
+	return items, due
+}
+
+func isDue(schedule model.Schedule, now time.Time) bool {
+	return !schedule.Paused && !schedule.Next.After(now)
+}
+
+// ExecuteScheduleCommand applies a `/schedule` subcommand to the schedules of a list and returns a reply. Schedules
+// of the config can be paused, but only removed from the config.
+func ExecuteScheduleCommand(data discordgo.ApplicationCommandInteractionData, scheduleClient model.ScheduleClient, author string) (string, error) {
+	if len(data.Options) == 0 {
+		return "", fmt.Errorf("missing subcommand")
+	}
+	subcommand := data.Options[0]
+	options := CommandOptions(data)
+
+	switch subcommand.Name {
+	case AddSubcommand:
+		schedule, err := ParseSchedule(options[EntryOption].StringValue(), time.Now())
+		if err != nil {
+			return "", err
+		}
+		schedule.Author = author
+		id, err := scheduleClient.AddSchedule(schedule)
+		if err != nil {
+			return "", err
+		} else if id == 0 {
+			return "", fmt.Errorf("`%s` is already scheduled every %s", schedule.Item, schedule.Every)
+		}
+		return fmt.Sprintf("Scheduled `%s` every %s, next on %s", schedule.Item, schedule.Every, schedule.Next.Format("02.01. 15:04")), nil
+	case ListSubcommand:
+		schedules, err := scheduleClient.GetSchedules()
+		if err != nil {
+			return "", err
+		} else if len(schedules) == 0 {
+			return "There are no schedules", nil
+		}
+		return model.ToScheduleTable(schedules), nil
+	case PauseSubcommand, ResumeSubcommand:
+		schedule, err := findSchedule(scheduleClient, int(options[ScheduleIDOption].IntValue()))
+		if err != nil {
+			return "", err
+		}
+		schedule.Paused = subcommand.Name == PauseSubcommand
+		if !schedule.Paused {
+			if schedule.Next, err = NextRun(schedule.Every, time.Now()); err != nil {
+				return "", err
+			}
+		}
+		if err := scheduleClient.UpdateSchedule(schedule); err != nil {
+			return "", err
+		}
+		if schedule.Paused {
+			return fmt.Sprintf("Paused schedule %d of `%s`", schedule.ID, schedule.Item), nil
+		}
+		return fmt.Sprintf("Resumed schedule %d of `%s`, next on %s", schedule.ID, schedule.Item, schedule.Next.Format("02.01. 15:04")), nil
+	case DeleteSubcommand:
+		schedule, err := findSchedule(scheduleClient, int(options[ScheduleIDOption].IntValue()))
+		if err != nil {
+			return "", err
+		} else if isConfigured(schedule) {
+			return "", fmt.Errorf("schedule %d of `%s` is configured and can only be removed from the config, pause it instead", schedule.ID, schedule.Item)
+		}
+		if err := scheduleClient.RemoveSchedule(schedule.ID); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Deleted schedule %d", schedule.ID), nil
+	}
+	return "", fmt.Errorf("unknown subcommand `%s`", subcommand.Name)
+}
+
+func findSchedule(scheduleClient model.ScheduleClient, id int) (model.Schedule, error) {
+	schedules, err := scheduleClient.GetSchedules()
+	if err != nil {
+		return model.Schedule{}, err
+	}
+	for _, schedule := range schedules {
+		if schedule.ID == id {
+			return schedule, nil
+		}
+	}
+	return model.Schedule{}, fmt.Errorf("there is no schedule %d", id)
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"fmt"
+	"github.com/bwmarrin/discordgo"
+	"github.com/maribowman/roastbeef-swag/app/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	// given
+	now := time.Date(2026, 10, 18, 9, 30, 0, 0, time.Local) // sunday
+	tests := map[string]struct {
+		entry         string
+		expectedItem  string
+		expectedEvery string
+		expectedNext  time.Time
+	}{
+		"weekday": {
+			entry:         "milk 2 every Monday",
+			expectedItem:  "milk 2",
+			expectedEvery: "monday",
+			expectedNext:  time.Date(2026, 10, 19, 8, 0, 0, 0, time.Local),
+		},
+		"same weekday": {
+			entry:         "bread every sunday",
+			expectedItem:  "bread",
+			expectedEvery: "sunday",
+			expectedNext:  time.Date(2026, 10, 25, 8, 0, 0, 0, time.Local),
+		},
+		"weeks": {
+			entry:         "toilet paper every 3 weeks",
+			expectedItem:  "toilet paper",
+			expectedEvery: "3 weeks",
+			expectedNext:  time.Date(2026, 11, 8, 8, 0, 0, 0, time.Local),
+		},
+		"day with time": {
+			entry:         "2 l water every day at 18:30",
+			expectedItem:  "2 l water",
+			expectedEvery: "day at 18:30",
+			expectedNext:  time.Date(2026, 10, 19, 18, 30, 0, 0, time.Local),
+		},
+		"month": {
+			entry:         "coffee every month",
+			expectedItem:  "coffee",
+			expectedEvery: "month",
+			expectedNext:  time.Date(2026, 11, 18, 8, 0, 0, 0, time.Local),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// when
+			schedule, err := ParseSchedule(test.entry, now)
+
+			// then
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedItem, schedule.Item)
+			assert.Equal(t, test.expectedEvery, schedule.Every)
+			assert.Equal(t, test.expectedNext, schedule.Next)
+		})
+	}
+
+	// and
+	for _, entry := range []string{"milk 2", "milk every fortnight", "milk every 0 days", "milk every 2 mondays", "milk every day at 25:00"} {
+		_, err := ParseSchedule(entry, now)
+		assert.Error(t, err, entry)
+	}
+}
+
+func TestAddRecurringItems(t *testing.T) {
+	// given
+	now := time.Date(2026, 10, 19, 8, 0, 0, 0, time.Local) // monday
+	items := []model.PantryItem{{ID: 1, Number: 1, Item: "milk", Amount: 1}}
+	schedules := []model.Schedule{
+		{ID: 1, Item: "milk 2", Every: "monday", Next: now},
+		{ID: 2, Item: "toilet paper", Every: "3 weeks", Next: now.AddDate(0, 0, -2)}, // missed while offline
+		{ID: 3, Item: "coffee", Every: "week", Next: now.AddDate(0, 0, 1)},
+		{ID: 4, Item: "beer 6", Every: "day", Next: now, Paused: true},
+	}
+
+	// when
+	actual, due := AddRecurringItems(items, schedules, now)
+
+	// then
+	assert.Len(t, actual, 2)
+	assert.Equal(t, 3.0, actual[0].Amount)
+	assert.Equal(t, "toilet paper", actual[1].Item)
+	assert.Equal(t, []model.Schedule{
+		{ID: 1, Item: "milk 2", Every: "monday", Next: now.AddDate(0, 0, 7)},
+		{ID: 2, Item: "toilet paper", Every: "3 weeks", Next: now.AddDate(0, 0, 21)},
+	}, due)
+	assert.Equal(t, 1.0, items[0].Amount) // original list stays untouched
+}
+
+// scheduleCommand creates the data of a `/schedule` subcommand.
+func scheduleCommand(subcommand string, options ...*discordgo.ApplicationCommandInteractionDataOption) discordgo.ApplicationCommandInteractionData {
+	return discordgo.ApplicationCommandInteractionData{
+		Name: ScheduleCommand,
+		Options: []*discordgo.ApplicationCommandInteractionDataOption{
+			{Name: subcommand, Type: discordgo.ApplicationCommandOptionSubCommand, Options: options},
+		},
+	}
+}
+
+func TestSyncSchedules(t *testing.T) {
+	// given
+	channel := testListChannel("groceries", "1")
+	channel.Recurring = []string{"milk 2 every monday", "soap every 4 weeks"}
+	_, handlers := newTestListHandlers(t, channel)
+	scheduleClient := handlers["groceries"].scheduleClient
+	_, err := ExecuteScheduleCommand(scheduleCommand(AddSubcommand,
+		&discordgo.ApplicationCommandInteractionDataOption{Name: EntryOption, Type: discordgo.ApplicationCommandOptionString, Value: "bread every friday"},
+	), scheduleClient, "mari")
+	assert.NoError(t, err)
+	_, err = ExecuteScheduleCommand(scheduleCommand(PauseSubcommand,
+		&discordgo.ApplicationCommandInteractionDataOption{Name: ScheduleIDOption, Type: discordgo.ApplicationCommandOptionInteger, Value: 1.0},
+	), scheduleClient, "mari")
+	assert.NoError(t, err)
+
+	// when
+	_, err = ExecuteScheduleCommand(scheduleCommand(DeleteSubcommand,
+		&discordgo.ApplicationCommandInteractionDataOption{Name: ScheduleIDOption, Type: discordgo.ApplicationCommandOptionInteger, Value: 2.0},
+	), scheduleClient, "mari")
+
+	// then
+	assert.ErrorContains(t, err, "configured")
+
+	// and when
+	err = SyncSchedules(scheduleClient, []string{"milk 2 every monday"}, time.Now()) // restart without soap
+
+	// then
+	assert.NoError(t, err)
+	schedules, err := scheduleClient.GetSchedules()
+	assert.NoError(t, err)
+	assert.Len(t, schedules, 2)
+	assert.Equal(t, "milk 2", schedules[0].Item)
+	assert.True(t, schedules[0].Paused, "configured schedules keep their state")
+	assert.Equal(t, "bread", schedules[1].Item, "schedules added by users stay")
+}
+
+func TestListHandlerSplitsLongScheduleList(t *testing.T) {
+	// given
+	session, handlers := newTestListHandlers(t, testListChannel("groceries", "1"))
+	groceries := handlers["groceries"]
+	for index := range 60 {
+		_, err := groceries.scheduleClient.AddSchedule(model.Schedule{Item: fmt.Sprintf("long item name %d", index), Every: "monday", Next: time.Now()})
+		assert.NoError(t, err)
+	}
+
+	// when
+	groceries.ApplicationCommandInteractionEvent(session, commandInteraction("1", "mari", scheduleCommand(ListSubcommand)))
+
+	// then
+	response := session.lastResponse()
+	assert.LessOrEqual(t, len(response.Data.Content), maxMessageLength)
+	assert.NotEmpty(t, session.followups)
+	for _, followup := range session.followups {
+		assert.LessOrEqual(t, len(followup.Content), maxMessageLength)
+	}
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"github.com/maribowman/roastbeef-swag/app/config"
+	"github.com/maribowman/roastbeef-swag/app/model"
+	"slices"
+	"time"
+)
+
+const (
+	RestockNote  = "auto-added"
+	restockActor = "restock"
+)
+
+// RestockItems returns the items to put on the restock list, because a change of the list let their stock fall below
+// the minimum of a rule. Item rules restock the item of the rule, category rules restock the consumed items.
+func RestockItems(restock config.Restock, items, updatedItems []model.PantryItem, categories map[string][]string, synonyms map[string]string) []model.PantryItem {
+	var restockItems []model.PantryItem
+	for _, rule := range restock.Rules {
+		if stock(rule, items, categories, synonyms) < rule.Minimum || stock(rule, updatedItems, categories, synonyms) >= rule.Minimum {
+			continue
+		}
+
+		names := []string{rule.Item}
+		if rule.Category != "" {
+			names = consumedItems(rule, items, updatedItems, categories, synonyms)
+		}
+		for _, name := range names {
+			restockItems = append(restockItems, model.PantryItem{
+				Item:   name,
+				Amount: rule.Amount,
+				Date:   time.Now().Truncate(time.Minute),
+				Note:   RestockNote,
+			})
+		}
+	}
+	return restockItems
+}
+
+// AddRestockItems appends all restock items which are not on the list yet.
+func AddRestockItems(items, restockItems []model.PantryItem, synonyms map[string]string) []model.PantryItem {
+	for _, restockItem := range restockItems {
+		name := model.NormalizeName(restockItem.Item, synonyms)
+		if slices.ContainsFunc(items, func(item model.PantryItem) bool {
+			return model.NormalizeName(item.Item, synonyms) == name
+		}) {
+			continue
+		}
+		restockItem.Number = len(items) + 1
+		items = append(items, restockItem)
+	}
+	return items
+}
+
+func matchesRule(rule config.RestockRule, item model.PantryItem, categories map[string][]string, synonyms map[string]string) bool {
+	if rule.Category != "" {
+		return model.CategoryOf(item, categories) == rule.Category
+	}
+	return model.NormalizeName(item.Item, synonyms) == model.NormalizeName(rule.Item, synonyms)
+}
+
+func stock(rule config.RestockRule, items []model.PantryItem, categories map[string][]string, synonyms map[string]string) float64 {
+	var stock float64
+	for _, item := range items {
+		if matchesRule(rule, item, categories, synonyms) {
+			stock += item.Amount
+		}
+	}
+	return stock
+}
+
+// consumedItems returns the names of all items of the rule which were removed or decreased.
+func consumedItems(rule config.RestockRule, items, updatedItems []model.PantryItem, categories map[string][]string, synonyms map[string]string) []string {
+	var names []string
+	for _, item := range items {
+		if !matchesRule(rule, item, categories, synonyms) || slices.Contains(names, item.Item) {
+			continue
+		}
+		index := slices.IndexFunc(updatedItems, func(updatedItem model.PantryItem) bool {
+			return updatedItem.ID == item.ID
+		})
+		if index == -1 || updatedItems[index].Amount < item.Amount {
+			names = append(names, item.Item)
+		}
+	}
+	return names
+}
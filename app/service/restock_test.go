@@ -0,0 +1,76 @@
+package service
+
+import (
+	"github.com/maribowman/roastbeef-swag/app/config"
+	"github.com/maribowman/roastbeef-swag/app/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRestockItems(t *testing.T) {
+	// given
+	restock := config.Restock{
+		List: "groceries",
+		Rules: []config.RestockRule{
+			{Item: "pizza", Minimum: 2, Amount: 4},
+			{Category: "fish", Minimum: 1, Amount: 1},
+		},
+	}
+	categories := map[string][]string{"fish": {"salmon", "cod"}}
+	items := []model.PantryItem{
+		{ID: 1, Number: 1, Item: "pizzas", Amount: 2},
+		{ID: 2, Number: 2, Item: "salmon", Amount: 1},
+		{ID: 3, Number: 3, Item: "peas", Amount: 1},
+	}
+	tests := map[string]struct {
+		updatedItems []model.PantryItem
+		expected     []string
+	}{
+		"stock above minimum": {
+			updatedItems: items,
+		},
+		"item falls below minimum": {
+			updatedItems: []model.PantryItem{{ID: 1, Number: 1, Item: "pizzas", Amount: 1}, items[1], items[2]},
+			expected:     []string{"4 pizza"},
+		},
+		"category runs out": {
+			updatedItems: []model.PantryItem{items[0], items[2]},
+			expected:     []string{"salmon"},
+		},
+		"item without rule runs out": {
+			updatedItems: items[:2],
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// when
+			actual := RestockItems(restock, items, test.updatedItems, categories, nil)
+
+			// then
+			var descriptions []string
+			for _, item := range actual {
+				assert.Equal(t, RestockNote, item.Note)
+				descriptions = append(descriptions, model.DescribeItem(item))
+			}
+			assert.Equal(t, test.expected, descriptions)
+		})
+	}
+}
+
+func TestAddRestockItems(t *testing.T) {
+	// given
+	groceries := []model.PantryItem{{ID: 1, Number: 1, Item: "Salmon", Amount: 1}}
+
+	// when
+	actual := AddRestockItems(groceries, []model.PantryItem{
+		{Item: "salmon", Amount: 1, Note: RestockNote},
+		{Item: "pizza", Amount: 4, Note: RestockNote},
+	}, nil)
+
+	// then
+	assert.EqualValues(t, []model.PantryItem{
+		{ID: 1, Number: 1, Item: "Salmon", Amount: 1},
+		{Number: 2, Item: "pizza", Amount: 4, Note: RestockNote},
+	}, actual)
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"time"
+)
+
+// scheduleDaily runs the task every day at the given time of day like `08:00` until the process ends.
+func scheduleDaily(at string, task func()) error {
+	clock, err := time.Parse("15:04", at)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		for {
+			time.Sleep(time.Until(nextDailyRun(time.Now(), clock)))
+			task()
+		}
+	}()
+	return nil
+}
+
+// scheduleEvery runs the task in the given interval until the process ends.
+func scheduleEvery(interval time.Duration, task func()) {
+	go func() {
+		for range time.Tick(interval) {
+			task()
+		}
+	}()
+}
+
+// nextDailyRun returns the next time after now at the hour and minute of clock.
+func nextDailyRun(now, clock time.Time) time.Time {
+	next := time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, now.Location())
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"fmt"
+	"github.com/bwmarrin/discordgo"
+	"github.com/maribowman/roastbeef-swag/app/model"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	TickButton       = "tick-button"
+	TickItemsSelect  = "tick-items-select"
+	FinishTripButton = "finish-trip-button"
+)
+
+// UpdateShoppingItems works like UpdateItems, but numbers tick items off instead of removing them, so the numbers of
+// the list stay the same during a shopping trip. Entering the number of a ticked item unticks it again. Expressions
+// starting with `*` still remove items.
+func UpdateShoppingItems(items []model.PantryItem, content string) []model.PantryItem {
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		if removeRegex.MatchString(line) && !strings.HasPrefix(line, "*") {
+			items = tick(items, line)
+		} else {
+			items = UpdateItems(items, line)
+		}
+	}
+	return items
+}
+
+// tick toggles the ticks of all items matched by an expression like `2 4 6-9`.
+func tick(items []model.PantryItem, line string) []model.PantryItem {
+	selected := selectNumbers(items, line)
+	tickedItems := slices.Clone(items) // keep the original list intact for the history
+	for index := range tickedItems {
+		if slices.Contains(selected, tickedItems[index].Number) {
+			tickedItems[index].Checked = !tickedItems[index].Checked
+		}
+	}
+	return tickedItems
+}
+
+// TickItems ticks exactly the items with the given IDs among the items offered by CreateTickItemsResponse.
+func TickItems(items []model.PantryItem, ids []int) []model.PantryItem {
+	tickedItems := slices.Clone(items)
+	for index := range tickedItems[:min(len(tickedItems), maxSelectOptions)] {
+		tickedItems[index].Checked = slices.Contains(ids, tickedItems[index].ID)
+	}
+	return tickedItems
+}
+
+// FinishTrip clears all ticked items from the list. The cleared items are returned and recorded as purchased by
+// CollectRemovals.
+func FinishTrip(items []model.PantryItem) ([]model.PantryItem, []model.PantryItem) {
+	remaining := []model.PantryItem{}
+	var bought []model.PantryItem
+	for _, item := range items {
+		if item.Checked {
+			bought = append(bought, item)
+			continue
+		}
+		item.Number = len(remaining) + 1
+		remaining = append(remaining, item)
+	}
+	return remaining, bought
+}
+
+// CreateTickItemsResponse asks only the user who pressed the tick button which items are in the cart. Ticked items
+// are preselected and Discord limits the selection to the first 25 items.
+func CreateTickItemsResponse(items []model.PantryItem) *discordgo.InteractionResponse {
+	var options []discordgo.SelectMenuOption
+	for _, item := range items[:min(len(items), maxSelectOptions)] {
+		options = append(options, discordgo.SelectMenuOption{
+			Label:   fmt.Sprintf("%d - %s", item.Number, model.DescribeItem(item)),
+			Value:   strconv.Itoa(item.ID),
+			Default: item.Checked,
+		})
+	}
+	if len(options) == 0 {
+		return CreateCommandResponse("There are no items to tick off")
+	}
+
+	minValues := 0
+	return &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: "Which items are in the cart?",
+			Flags:   discordgo.MessageFlagsEphemeral,
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.SelectMenu{
+							CustomID:    TickItemsSelect,
+							Placeholder: "Items",
+							MinValues:   &minValues,
+							MaxValues:   len(options),
+							Options:     options,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// describePurchases confirms the items bought on a shopping trip to the user.
+func describePurchases(bought []model.PantryItem) string {
+	if len(bought) == 0 {
+		return "There are no ticked items to clear"
+	}
+	var names []string
+	for _, item := range bought {
+		names = append(names, model.DescribeItem(item))
+	}
+	return fmt.Sprintf("Bought %s", strings.Join(names, ", "))
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"github.com/maribowman/roastbeef-swag/app/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestUpdateShoppingItems(t *testing.T) {
+	// given
+	items := []model.PantryItem{
+		{ID: 11, Number: 1, Item: "eggs", Amount: 6},
+		{ID: 12, Number: 2, Item: "milk", Amount: 1, Checked: true},
+		{ID: 13, Number: 3, Item: "coffee", Amount: 1},
+		{ID: 14, Number: 4, Item: "peas", Amount: 1},
+	}
+
+	// when
+	actual := UpdateShoppingItems(items, "1 2\n3-4\n4")
+
+	// then
+	assert.Len(t, actual, 4)
+	assert.Equal(t, []bool{true, false, true, false}, []bool{actual[0].Checked, actual[1].Checked, actual[2].Checked, actual[3].Checked})
+	assert.Equal(t, []int{1, 2, 3, 4}, []int{actual[0].Number, actual[1].Number, actual[2].Number, actual[3].Number})
+	assert.False(t, items[0].Checked) // original list stays untouched
+
+	// and adding more of a ticked item unticks it
+	actual = UpdateShoppingItems(actual, "eggs 2\n* 1 3")
+	assert.Len(t, actual, 2)
+	assert.Equal(t, "eggs", actual[0].Item)
+	assert.Equal(t, 8.0, actual[0].Amount)
+	assert.False(t, actual[0].Checked)
+	assert.Equal(t, "coffee", actual[1].Item)
+}
+
+func TestTickItems(t *testing.T) {
+	// given
+	items := []model.PantryItem{
+		{ID: 11, Number: 1, Item: "eggs", Checked: true},
+		{ID: 12, Number: 2, Item: "milk"},
+		{ID: 13, Number: 3, Item: "coffee"},
+	}
+
+	// when
+	actual := TickItems(items, []int{12, 13})
+
+	// then
+	assert.Equal(t, []bool{false, true, true}, []bool{actual[0].Checked, actual[1].Checked, actual[2].Checked})
+}
+
+func TestFinishTrip(t *testing.T) {
+	// given
+	items := []model.PantryItem{
+		{ID: 11, Number: 1, Item: "eggs", Amount: 6, Checked: true},
+		{ID: 12, Number: 2, Item: "milk", Amount: 1.5, Unit: "l", Checked: true},
+		{ID: 13, Number: 3, Item: "coffee", Amount: 1},
+	}
+
+	// when
+	remaining, bought := FinishTrip(items)
+
+	// then
+	assert.Equal(t, []model.PantryItem{{ID: 13, Number: 1, Item: "coffee", Amount: 1}}, remaining)
+	assert.Equal(t, items[:2], bought)
+	assert.Equal(t, "Bought 6 eggs, 1.5 l milk", describePurchases(bought))
+
+	// and
+	remaining, bought = FinishTrip(remaining)
+	assert.Len(t, remaining, 1)
+	assert.Empty(t, bought)
+}
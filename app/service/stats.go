@@ -0,0 +1,52 @@
+package service
+
+import (
+	"github.com/maribowman/roastbeef-swag/app/config"
+	"github.com/maribowman/roastbeef-swag/app/model"
+	"time"
+)
+
+// correctionWindow is the time after adding an item in which removing it counts as correcting a mistake.
+const correctionWindow = 15 * time.Minute
+
+// CollectRemovals returns every item of items which is missing in storedItems. Items are matched by their database ID.
+// Ticked items count as purchased, items removed within the correction window as corrected and others as cleared.
+func CollectRemovals(items, storedItems []model.PantryItem, author string, date time.Time) []model.Removal {
+	storedIDs := map[int]bool{}
+	for _, item := range storedItems {
+		storedIDs[item.ID] = true
+	}
+
+	var removals []model.Removal
+	for _, item := range items {
+		if storedIDs[item.ID] {
+			continue
+		}
+		reason := model.ClearedReason
+		if item.Checked {
+			reason = model.PurchasedReason
+		} else if date.Sub(item.Date) < correctionWindow {
+			reason = model.RemovedReason
+		}
+		removals = append(removals, model.Removal{
+			Item:     item.Item,
+			Amount:   item.Amount,
+			Unit:     item.Unit,
+			Category: model.CategoryOf(item, config.Config.Discord.Categories),
+			Author:   author,
+			Reason:   reason,
+			Added:    item.Date,
+			Removed:  date,
+		})
+	}
+	return removals
+}
+
+// CalculateStats aggregates all removals of a list, see model.CalculateStats.
+func CalculateStats(list model.ListHandler, name string) (model.Stats, error) {
+	removals, err := list.GetRemovals(time.Time{})
+	if err != nil {
+		return model.Stats{}, err
+	}
+	return model.CalculateStats(name, removals, config.Config.Discord.Synonyms, time.Now()), nil
+}
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"github.com/maribowman/roastbeef-swag/app/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestCollectRemovals(t *testing.T) {
+	// given
+	date := time.Date(2023, 12, 27, 18, 0, 0, 0, time.Local)
+	items := []model.PantryItem{
+		{ID: 11, Number: 1, Item: "eggs", Amount: 6, Date: date.AddDate(0, 0, -2), Checked: true},
+		{ID: 12, Number: 2, Item: "salmon", Amount: 2, Date: date.AddDate(0, 0, -1), Category: "fish"},
+		{ID: 13, Number: 3, Item: "cofee", Amount: 1, Date: date.Add(-5 * time.Minute)},
+		{ID: 14, Number: 4, Item: "coffee", Amount: 1, Date: date},
+	}
+
+	// when
+	removals := CollectRemovals(items, items[3:], "mari", date)
+
+	// then
+	assert.Equal(t, []model.Removal{
+		{Item: "eggs", Amount: 6, Author: "mari", Reason: model.PurchasedReason, Added: date.AddDate(0, 0, -2), Removed: date},
+		{Item: "salmon", Amount: 2, Category: "fish", Author: "mari", Reason: model.ClearedReason, Added: date.AddDate(0, 0, -1), Removed: date},
+		{Item: "cofee", Amount: 1, Author: "mari", Reason: model.RemovedReason, Added: date.Add(-5 * time.Minute), Removed: date},
+	}, removals)
+}
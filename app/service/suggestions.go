@@ -0,0 +1,146 @@
+package service
+
+import (
+	"fmt"
+	"github.com/bwmarrin/discordgo"
+	"github.com/maribowman/roastbeef-swag/app/model"
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	SuggestButton      = "suggest-button"
+	SuggestItemsSelect = "suggest-items-select"
+
+	minCadencePurchases = 3   // purchases needed to learn the interval of an item
+	cadenceMonths       = 6   // only purchases of the last months are considered
+	dueRatio            = 0.9 // items are suggested shortly before their typical interval has passed
+)
+
+// SuggestItems learns the typical interval between purchases of every item from the removals of a list and returns
+// the items which are due, most overdue first. Items already on the list are never suggested. Only bought or cleared
+// items count like in the stats, removals on the same day count as a single purchase.
+func SuggestItems(removals []model.Removal, items []model.PantryItem, synonyms map[string]string, now time.Time) []model.Suggestion {
+	purchases := map[string][]model.Removal{}
+	var names []string
+	for _, removal := range removals {
+		if !removal.IsConsumption() || removal.Removed.Before(now.AddDate(0, -cadenceMonths, 0)) {
+			continue
+		}
+		name := model.NormalizeName(removal.Item, synonyms)
+		if previous := purchases[name]; len(previous) != 0 && removal.Removed.Sub(previous[len(previous)-1].Removed) < 24*time.Hour {
+			previous[len(previous)-1] = removal
+			continue
+		}
+		if _, ok := purchases[name]; !ok {
+			names = append(names, name)
+		}
+		purchases[name] = append(purchases[name], removal)
+	}
+
+	var suggestions []model.Suggestion
+	for _, name := range names {
+		if len(purchases[name]) < minCadencePurchases || slices.ContainsFunc(items, func(item model.PantryItem) bool {
+			return model.NormalizeName(item.Item, synonyms) == name
+		}) {
+			continue
+		}
+
+		var intervals []float64
+		for index := 1; index < len(purchases[name]); index++ {
+			intervals = append(intervals, purchases[name][index].Removed.Sub(purchases[name][index-1].Removed).Hours()/24)
+		}
+		slices.Sort(intervals)
+		interval := intervals[len(intervals)/2]
+
+		last := purchases[name][len(purchases[name])-1]
+		if now.Sub(last.Removed).Hours()/24 < dueRatio*interval {
+			continue
+		}
+		suggestions = append(suggestions, model.Suggestion{
+			Item:     last.Item,
+			Amount:   last.Amount,
+			Unit:     last.Unit,
+			Interval: math.Round(interval),
+			Last:     last.Removed,
+		})
+	}
+
+	overdue := func(suggestion model.Suggestion) float64 {
+		return now.Sub(suggestion.Last).Hours() / 24 / suggestion.Interval
+	}
+	slices.SortStableFunc(suggestions, func(a, b model.Suggestion) int {
+		return -compareFloats(overdue(a), overdue(b))
+	})
+	return suggestions[:min(len(suggestions), maxSelectOptions)]
+}
+
+func compareFloats(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// AddSuggestedItems adds the chosen suggestions with the amount of their last purchase.
+func AddSuggestedItems(items []model.PantryItem, suggestions []model.Suggestion, chosen []string) []model.PantryItem {
+	for _, suggestion := range suggestions {
+		if !slices.Contains(chosen, suggestion.Item) {
+			continue
+		}
+		items = MergeItem(items, model.PantryItem{
+			Item:   suggestion.Item,
+			Amount: suggestion.Amount,
+			Unit:   suggestion.Unit,
+			Date:   time.Now().Truncate(time.Minute),
+		})
+	}
+	return items
+}
+
+// CreateSuggestionsResponse offers only the user who pressed the suggestions button the items which are due.
+func CreateSuggestionsResponse(suggestions []model.Suggestion, now time.Time) *discordgo.InteractionResponse {
+	if len(suggestions) == 0 {
+		return CreateCommandResponse("Nothing is due yet")
+	}
+
+	var options []discordgo.SelectMenuOption
+	for _, suggestion := range suggestions {
+		options = append(options, discordgo.SelectMenuOption{
+			Label: model.DescribeItem(model.PantryItem{Item: suggestion.Item, Amount: suggestion.Amount, Unit: suggestion.Unit}),
+			Value: suggestion.Item,
+			Description: fmt.Sprintf("every ~%.0f days, last bought %.0f days ago",
+				suggestion.Interval, math.Floor(now.Sub(suggestion.Last).Hours()/24)),
+		})
+	}
+
+	return &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: "These items are probably due, which ones to add?",
+			Flags:   discordgo.MessageFlagsEphemeral,
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.SelectMenu{
+							CustomID:    SuggestItemsSelect,
+							Placeholder: "Items",
+							MaxValues:   len(options),
+							Options:     options,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// describeSuggestions confirms the added suggestions to the user.
+func describeSuggestions(chosen []string) string {
+	return fmt.Sprintf("Added %s", strings.Join(chosen, ", "))
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"github.com/maribowman/roastbeef-swag/app/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestSuggestItems(t *testing.T) {
+	// given
+	now := time.Date(2024, 2, 1, 12, 0, 0, 0, time.Local)
+	removed := func(item string, daysAgo int, amount float64) model.Removal {
+		return model.Removal{Item: item, Amount: amount, Reason: model.ClearedReason, Removed: now.AddDate(0, 0, -daysAgo)}
+	}
+	removals := []model.Removal{
+		removed("coffee", 40, 1), removed("eggs", 35, 6), removed("milk", 30, 1),
+		removed("coffee", 30, 1), removed("eggs", 28, 6), removed("milk", 29, 1),
+		removed("coffee", 20, 1), removed("eggs", 21, 6), removed("milk", 28, 1),
+		removed("coffee", 20, 1), // same day as the previous purchase
+		removed("eggs", 14, 10), removed("milk", 27, 2),
+		removed("flour", 20, 1), removed("flour", 10, 1), // too few purchases
+		removed("butter", 60, 1), removed("butter", 50, 1), removed("butter", 40, 1), // on the list already
+	}
+	items := []model.PantryItem{{Number: 1, Item: "Butter", Amount: 1}}
+
+	// when
+	suggestions := SuggestItems(removals, items, nil, now)
+
+	// then
+	assert.Equal(t, []model.Suggestion{
+		{Item: "milk", Amount: 2, Interval: 1, Last: now.AddDate(0, 0, -27)},
+		{Item: "coffee", Amount: 1, Interval: 10, Last: now.AddDate(0, 0, -20)},
+		{Item: "eggs", Amount: 10, Interval: 7, Last: now.AddDate(0, 0, -14)},
+	}, suggestions)
+
+	// and
+	added := AddSuggestedItems(items, suggestions, []string{"coffee", "eggs"})
+	assert.Len(t, added, 3)
+	assert.Equal(t, "coffee", added[1].Item)
+	assert.Equal(t, 10.0, added[2].Amount)
+}
+
+func TestSuggestItemsNotDueYet(t *testing.T) {
+	// given
+	now := time.Date(2024, 2, 1, 12, 0, 0, 0, time.Local)
+	removals := []model.Removal{
+		{Item: "coffee", Amount: 1, Reason: model.PurchasedReason, Removed: now.AddDate(0, 0, -25)},
+		{Item: "coffee", Amount: 1, Reason: model.PurchasedReason, Removed: now.AddDate(0, 0, -15)},
+		{Item: "coffee", Amount: 1, Reason: model.PurchasedReason, Removed: now.AddDate(0, 0, -5)},
+	}
+
+	// when
+	suggestions := SuggestItems(removals, nil, nil, now)
+
+	// then
+	assert.Empty(t, suggestions)
+}
+
+func TestSuggestItemsIgnoresCorrectionsAndMoves(t *testing.T) {
+	// given
+	now := time.Date(2024, 2, 1, 12, 0, 0, 0, time.Local)
+	var removals []model.Removal
+	for _, daysAgo := range []int{30, 20, 10} {
+		removals = append(removals,
+			model.Removal{Item: "cofee", Amount: 1, Reason: model.RemovedReason, Removed: now.AddDate(0, 0, -daysAgo)},
+			model.Removal{Item: "pizza", Amount: 2, Reason: model.MovedReason, Removed: now.AddDate(0, 0, -daysAgo)},
+		)
+	}
+
+	// when
+	suggestions := SuggestItems(removals, nil, nil, now)
+
+	// then
+	assert.Empty(t, suggestions)
+}